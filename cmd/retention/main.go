@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/didrikolofsson/materials/internal/config"
+	"github.com/didrikolofsson/materials/internal/infra/mysql"
+	"github.com/didrikolofsson/materials/internal/repositories"
+	"github.com/didrikolofsson/materials/internal/services"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report how many versions would be deleted without deleting them")
+	flag.Parse()
+
+	ctx := context.Background()
+	cfg := config.Load()
+	db := mysql.New(cfg.DBDsn)
+	defer db.Close()
+
+	svc := services.New(db, repositories.New(db))
+	policy := services.VersionRetentionPolicy{
+		KeepLast:   cfg.VersionRetentionKeepLast,
+		KeepTagged: cfg.VersionRetentionKeepTagged,
+		DailyAfter: cfg.VersionRetentionDailyAfter,
+	}
+
+	result, err := svc.ApplyVersionRetention(ctx, policy, time.Now(), *dryRun)
+	if err != nil {
+		log.Fatalf("failed to apply version retention: %v", err)
+	}
+
+	if result.DryRun {
+		log.Printf(
+			"Would delete %d of %d versions across %d materials",
+			result.VersionsDeleted,
+			result.VersionsScanned,
+			result.Materials,
+		)
+		return
+	}
+	log.Printf(
+		"Deleted %d of %d versions across %d materials and %d orphaned contents",
+		result.VersionsDeleted,
+		result.VersionsScanned,
+		result.Materials,
+		result.ContentsDeleted,
+	)
+}
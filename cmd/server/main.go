@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/didrikolofsson/materials/internal/config"
@@ -9,6 +10,7 @@ import (
 	"github.com/didrikolofsson/materials/internal/infra/mysql"
 	"github.com/didrikolofsson/materials/internal/repositories"
 	"github.com/didrikolofsson/materials/internal/services"
+	"github.com/didrikolofsson/materials/internal/worker"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -29,9 +31,36 @@ func main() {
 		handlers,
 	)
 
+	var jobs []worker.Job
+	if cfg.ProposalReminderAfter > 0 {
+		jobs = append(jobs, worker.ProposalReminders(svc, worker.LogNotifier{}, cfg.ProposalReminderAfter))
+	}
+	if cfg.ProposalExpireAfter > 0 {
+		jobs = append(jobs, worker.ProposalExpiry(svc, cfg.ProposalExpireAfter))
+	}
+	if cfg.VersionRetentionJob {
+		jobs = append(jobs, worker.VersionRetention(svc, services.VersionRetentionPolicy{
+			KeepLast:   cfg.VersionRetentionKeepLast,
+			KeepTagged: cfg.VersionRetentionKeepTagged,
+			DailyAfter: cfg.VersionRetentionDailyAfter,
+		}))
+	}
+	wrk := worker.New(cfg.JobInterval, jobs...)
+
+	// The worker runs until the server has shut down
+	ctx, cancel := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		wrk.Run(ctx)
+	}()
+
 	if err := srv.Run(); err != nil {
 		log.Fatalf("server shutdown error: %v", err)
 	}
 
+	cancel()
+	<-workerDone
+
 	log.Println("Server stopped")
 }
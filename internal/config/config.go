@@ -4,11 +4,26 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
 	Port  string
 	DBDsn string
+
+	// Background jobs. The proposal jobs are opt in: a zero duration, the
+	// default, disables that job
+	JobInterval           time.Duration
+	ProposalReminderAfter time.Duration
+	ProposalExpireAfter   time.Duration
+
+	// Version retention, applied by cmd/retention and, when VersionRetentionJob
+	// is set, as a background job
+	VersionRetentionJob        bool
+	VersionRetentionKeepLast   int
+	VersionRetentionKeepTagged bool
+	VersionRetentionDailyAfter time.Duration
 }
 
 func getEnv(key string, def string) string {
@@ -18,15 +33,80 @@ func getEnv(key string, def string) string {
 	return def
 }
 
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("%s is not a valid duration: %v", key, err)
+	}
+	return d
+}
+
+func getEnvInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf("%s is not a valid integer: %v", key, err)
+	}
+	return i
+}
+
+func getEnvBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Fatalf("%s is not a valid boolean: %v", key, err)
+	}
+	return b
+}
+
 func Load() Config {
 	cfg := Config{
 		Port:  getEnv("SERVER_PORT", "8080"),
 		DBDsn: getEnv("DB_DSN", "root:root@tcp(localhost:3306)/materials?parseTime=true"),
+
+		JobInterval:           getEnvDuration("JOB_INTERVAL", time.Hour),
+		ProposalReminderAfter: getEnvDuration("PROPOSAL_REMINDER_AFTER", 0),
+		ProposalExpireAfter:   getEnvDuration("PROPOSAL_EXPIRE_AFTER", 0),
+
+		VersionRetentionJob:        getEnvBool("VERSION_RETENTION_JOB", false),
+		VersionRetentionKeepLast:   getEnvInt("VERSION_RETENTION_KEEP_LAST", 20),
+		VersionRetentionKeepTagged: getEnvBool("VERSION_RETENTION_KEEP_TAGGED", true),
+		VersionRetentionDailyAfter: getEnvDuration("VERSION_RETENTION_DAILY_AFTER", 30*24*time.Hour),
 	}
 
 	if cfg.Port == "" {
 		log.Fatal("SERVER_PORT is not set")
 	}
 
+	if cfg.JobInterval <= 0 {
+		log.Fatal("JOB_INTERVAL must be positive")
+	}
+
+	if cfg.ProposalReminderAfter < 0 {
+		log.Fatal("PROPOSAL_REMINDER_AFTER must not be negative")
+	}
+
+	if cfg.ProposalExpireAfter < 0 {
+		log.Fatal("PROPOSAL_EXPIRE_AFTER must not be negative")
+	}
+
+	if cfg.VersionRetentionKeepLast < 0 {
+		log.Fatal("VERSION_RETENTION_KEEP_LAST must not be negative")
+	}
+
+	if cfg.VersionRetentionDailyAfter < 0 {
+		log.Fatal("VERSION_RETENTION_DAILY_AFTER must not be negative")
+	}
+
 	return cfg
 }
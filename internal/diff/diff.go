@@ -0,0 +1,187 @@
+// Package diff provides line and word level text diffing.
+package diff
+
+import (
+	"regexp"
+	"strings"
+)
+
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Edit is a run of text that was kept, inserted or deleted. Concatenating
+// the equal and delete edits yields the old text, and the equal and insert
+// edits yield the new text.
+type Edit struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+var wordPattern = regexp.MustCompile(`\s+|\S+`)
+
+// SplitLines splits s into lines, keeping the trailing newline on each line.
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func splitWords(s string) []string {
+	return wordPattern.FindAllString(s, -1)
+}
+
+// Lines diffs a and b line by line.
+func Lines(a, b string) []Edit {
+	al, bl := SplitLines(a), SplitLines(b)
+	return group(al, bl, Tokens(al, bl))
+}
+
+// Words diffs a and b word by word, treating runs of whitespace as words.
+func Words(a, b string) []Edit {
+	aw, bw := splitWords(a), splitWords(b)
+	return group(aw, bw, Tokens(aw, bw))
+}
+
+// Step is a single token operation. For equal steps both indexes are set,
+// for deletes only A and for inserts only B; the unused index is -1.
+type Step struct {
+	Op Op
+	A  int
+	B  int
+}
+
+// Tokens returns the shortest edit script turning a into b.
+func Tokens(a, b []string) []Step {
+	// Common prefixes and suffixes are cheap to strip and keep the search small
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	steps := make([]Step, 0, len(a)+len(b))
+	for i := range prefix {
+		steps = append(steps, Step{Op: OpEqual, A: i, B: i})
+	}
+	for _, s := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		if s.A >= 0 {
+			s.A += prefix
+		}
+		if s.B >= 0 {
+			s.B += prefix
+		}
+		steps = append(steps, s)
+	}
+	for i := suffix; i > 0; i-- {
+		steps = append(steps, Step{Op: OpEqual, A: len(a) - i, B: len(b) - i})
+	}
+	return steps
+}
+
+// myers implements the O(ND) algorithm from Myers' "An O(ND) Difference
+// Algorithm and Its Variations", keeping one frontier per edit distance so the
+// path can be recovered.
+func myers(a, b []string) []Step {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+
+search:
+	for d := 0; d <= n+m; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+				break search
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+
+	var steps []Step
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		at := func(k int) int { return prev[k+d-1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			steps = append(steps, Step{Op: OpEqual, A: x - 1, B: y - 1})
+			x--
+			y--
+		}
+		if x == prevX {
+			steps = append(steps, Step{Op: OpInsert, A: -1, B: y - 1})
+			y--
+		} else {
+			steps = append(steps, Step{Op: OpDelete, A: x - 1, B: -1})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		steps = append(steps, Step{Op: OpEqual, A: x - 1, B: y - 1})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	return steps
+}
+
+// group joins consecutive steps with the same op into edits.
+func group(a, b []string, steps []Step) []Edit {
+	edits := []Edit{}
+	for _, s := range steps {
+		text := ""
+		if s.Op == OpInsert {
+			text = b[s.B]
+		} else {
+			text = a[s.A]
+		}
+		if len(edits) > 0 && edits[len(edits)-1].Op == s.Op {
+			edits[len(edits)-1].Text += text
+			continue
+		}
+		edits = append(edits, Edit{Op: s.Op, Text: text})
+	}
+	return edits
+}
@@ -0,0 +1,169 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"single line", "a\n", []string{"a\n"}},
+		{"missing trailing newline", "a\nb", []string{"a\n", "b"}},
+		{"blank lines", "\n\n", []string{"\n", "\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitLines(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitLines(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Edit
+	}{
+		{"both empty", "", "", []Edit{}},
+		{"from empty", "", "a\nb\n", []Edit{{OpInsert, "a\nb\n"}}},
+		{"to empty", "a\nb\n", "", []Edit{{OpDelete, "a\nb\n"}}},
+		{"equal", "a\nb\n", "a\nb\n", []Edit{{OpEqual, "a\nb\n"}}},
+		{
+			"changed line",
+			"a\nb\nc\n",
+			"a\nB\nc\n",
+			[]Edit{{OpEqual, "a\n"}, {OpDelete, "b\n"}, {OpInsert, "B\n"}, {OpEqual, "c\n"}},
+		},
+		{
+			"trailing newline added",
+			"a\nb",
+			"a\nb\n",
+			[]Edit{{OpEqual, "a\n"}, {OpDelete, "b"}, {OpInsert, "b\n"}},
+		},
+		{
+			"line appended after missing newline",
+			"a",
+			"a\nb",
+			[]Edit{{OpDelete, "a"}, {OpInsert, "a\nb"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWords(t *testing.T) {
+	got := Words("the quick fox", "the slow fox")
+	want := []Edit{{OpEqual, "the "}, {OpDelete, "quick"}, {OpInsert, "slow"}, {OpEqual, " fox"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Words = %v, want %v", got, want)
+	}
+}
+
+// TestTokensRebuildsBothSides checks the invariant the rest of the package
+// relies on: equal and delete steps walk a in order, equal and insert steps
+// walk b in order.
+func TestTokensRebuildsBothSides(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"both empty", "", ""},
+		{"disjoint", "a\nb\n", "c\nd\n"},
+		{"interleaved", "a\nb\nc\nd\ne\n", "b\nx\nd\ne\ny\n"},
+		{"repeated lines", "a\na\nb\na\n", "a\nb\na\na\n"},
+		{"prefix and suffix", "a\nb\nc\nd\n", "a\nx\nd\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := SplitLines(tt.a), SplitLines(tt.b)
+			var gotA, gotB []string
+			for _, s := range Tokens(a, b) {
+				if s.Op != OpInsert {
+					gotA = append(gotA, a[s.A])
+				}
+				if s.Op != OpDelete {
+					gotB = append(gotB, b[s.B])
+				}
+			}
+			if strings.Join(gotA, "") != tt.a || strings.Join(gotB, "") != tt.b {
+				t.Errorf("Tokens rebuilt %q and %q, want %q and %q",
+					strings.Join(gotA, ""), strings.Join(gotB, ""), tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{"both empty", "", "", ""},
+		{
+			"changed line",
+			"a\nb\nc\n",
+			"a\nB\nc\n",
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"from empty",
+			"",
+			"a\nb\n",
+			"--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			"to empty",
+			"a\n",
+			"",
+			"--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			"missing trailing newline",
+			"a\nb",
+			"a\nb\n",
+			"--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			"insertion after context",
+			"1\n2\n3\n4\n5\n",
+			"1\n2\n3\n4\n5\n6\n",
+			"--- old\n+++ new\n@@ -3,3 +3,4 @@\n 3\n 4\n 5\n+6\n",
+		},
+		{
+			"distant changes get separate hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			"x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n",
+			"--- old\n+++ new\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+y\n",
+		},
+		{
+			"close changes share a hunk",
+			"1\n2\n3\n4\n5\n6\n7\n",
+			"x\n2\n3\n4\n5\n6\ny\n",
+			"--- old\n+++ new\n@@ -1,7 +1,7 @@\n-1\n+x\n 2\n 3\n 4\n 5\n 6\n-7\n+y\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified(tt.a, tt.b, "old", "new"); got != tt.want {
+				t.Errorf("Unified(%q, %q) =\n%s\nwant\n%s", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
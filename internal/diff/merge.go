@@ -0,0 +1,172 @@
+package diff
+
+import "strings"
+
+// Conflict is a region where both sides changed the base differently. Line is
+// the 1-based line of the conflict's opening marker in the merged text.
+type Conflict struct {
+	Line   int    `json:"line"`
+	Base   string `json:"base"`
+	Ours   string `json:"ours"`
+	Theirs string `json:"theirs"`
+}
+
+// MergeResult is the outcome of a three-way merge. When there are conflicts,
+// Text contains diff3 style conflict markers around each conflicting region.
+type MergeResult struct {
+	Text      string
+	Conflicts []Conflict
+}
+
+// Merge performs a line based three-way merge of ours and theirs, which were
+// both derived from base. Changes conflict when they touch the same base
+// lines or insert at the same place; changes to neighbouring lines merge.
+// The labels are written next to the conflict markers.
+func Merge(base, ours, theirs, oursLabel, theirsLabel string) MergeResult {
+	baseLines := SplitLines(base)
+	oursHunks := changes(baseLines, SplitLines(ours))
+	theirsHunks := changes(baseLines, SplitLines(theirs))
+
+	var out strings.Builder
+	var conflicts []Conflict
+	line := 1
+	write := func(lines []string) {
+		for _, l := range lines {
+			out.WriteString(l)
+			line++
+		}
+	}
+
+	pos := 0
+	for len(oursHunks) > 0 || len(theirsHunks) > 0 {
+		// Start a region at the first remaining change and grow it over every
+		// change on either side that overlaps it
+		var o, t []hunk
+		var first hunk
+		if len(theirsHunks) == 0 || (len(oursHunks) > 0 && oursHunks[0].start <= theirsHunks[0].start) {
+			first, oursHunks = oursHunks[0], oursHunks[1:]
+			o = append(o, first)
+		} else {
+			first, theirsHunks = theirsHunks[0], theirsHunks[1:]
+			t = append(t, first)
+		}
+		start, end := first.start, first.end
+	grow:
+		for {
+			var next hunk
+			switch {
+			case len(oursHunks) > 0 && oursHunks[0].overlaps(start, end):
+				next, oursHunks = oursHunks[0], oursHunks[1:]
+				o = append(o, next)
+			case len(theirsHunks) > 0 && theirsHunks[0].overlaps(start, end):
+				next, theirsHunks = theirsHunks[0], theirsHunks[1:]
+				t = append(t, next)
+			default:
+				break grow
+			}
+			start, end = min(start, next.start), max(end, next.end)
+		}
+
+		write(baseLines[pos:start])
+		b := baseLines[start:end]
+		oursText, theirsText := apply(baseLines, start, end, o), apply(baseLines, start, end, t)
+		switch {
+		case len(t) == 0:
+			write(oursText)
+		case len(o) == 0, equalLines(oursText, theirsText):
+			write(theirsText)
+		default:
+			conflicts = append(conflicts, Conflict{
+				Line:   line,
+				Base:   strings.Join(b, ""),
+				Ours:   strings.Join(oursText, ""),
+				Theirs: strings.Join(theirsText, ""),
+			})
+			write([]string{"<<<<<<< " + oursLabel + "\n"})
+			write(terminated(oursText))
+			write([]string{"=======\n"})
+			write(terminated(theirsText))
+			write([]string{">>>>>>> " + theirsLabel + "\n"})
+		}
+		pos = end
+	}
+	write(baseLines[pos:])
+
+	return MergeResult{Text: out.String(), Conflicts: conflicts}
+}
+
+// hunk replaces the base lines [start, end) with lines. An insertion has an
+// empty range.
+type hunk struct {
+	start, end int
+	lines      []string
+}
+
+// overlaps reports whether h conflicts with a change of the base lines
+// [start, end): the ranges share a line, or both start at the same place, so
+// their order would be ambiguous.
+func (h hunk) overlaps(start, end int) bool {
+	return h.start == start || (h.start < end && start < h.end)
+}
+
+// changes returns the hunks turning base into side, in base order.
+func changes(base, side []string) []hunk {
+	var hunks []hunk
+	at := 0
+	open := false
+	for _, s := range Tokens(base, side) {
+		if s.Op == OpEqual {
+			at = s.A + 1
+			open = false
+			continue
+		}
+		if !open {
+			hunks = append(hunks, hunk{start: at, end: at})
+			open = true
+		}
+		h := &hunks[len(hunks)-1]
+		if s.Op == OpDelete {
+			h.end = s.A + 1
+			at = s.A + 1
+		} else {
+			h.lines = append(h.lines, side[s.B])
+		}
+	}
+	return hunks
+}
+
+// apply returns the base lines [start, end) with the hunks, which lie within
+// that range, applied.
+func apply(base []string, start, end int, hunks []hunk) []string {
+	out := []string{}
+	pos := start
+	for _, h := range hunks {
+		out = append(out, base[pos:h.start]...)
+		out = append(out, h.lines...)
+		pos = h.end
+	}
+	return append(out, base[pos:end]...)
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// terminated makes sure the last line ends with a newline so a following
+// conflict marker starts on its own line.
+func terminated(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+	out := append([]string(nil), lines...)
+	out[len(out)-1] += "\n"
+	return out
+}
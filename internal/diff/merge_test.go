@@ -0,0 +1,150 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		wantConflicts      []Conflict
+	}{
+		{
+			name: "all empty",
+		},
+		{
+			name: "into empty base",
+			ours: "a\n",
+			want: "a\n",
+		},
+		{
+			name:   "unchanged",
+			base:   "a\nb\n",
+			ours:   "a\nb\n",
+			theirs: "a\nb\n",
+			want:   "a\nb\n",
+		},
+		{
+			name:   "only ours changed",
+			base:   "a\nb\nc\n",
+			ours:   "a\nB\nc\n",
+			theirs: "a\nb\nc\n",
+			want:   "a\nB\nc\n",
+		},
+		{
+			name:   "only theirs changed",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\nC\n",
+			want:   "a\nb\nC\n",
+		},
+		{
+			name:   "identical edits on both sides",
+			base:   "a\nb\nc\n",
+			ours:   "a\nB\nc\nd\n",
+			theirs: "a\nB\nc\nd\n",
+			want:   "a\nB\nc\nd\n",
+		},
+		{
+			name:   "edits on adjacent lines",
+			base:   "a\nb\nc\n",
+			ours:   "A\nb\nc\n",
+			theirs: "a\nB\nc\n",
+			want:   "A\nB\nc\n",
+		},
+		{
+			name:   "delete next to an edit",
+			base:   "a\nb\nc\n",
+			ours:   "a\nc\n",
+			theirs: "a\nb\nC\n",
+			want:   "a\nC\n",
+		},
+		{
+			name:   "insert after an edited line",
+			base:   "a\nb\n",
+			ours:   "a\nx\nb\n",
+			theirs: "A\nb\n",
+			want:   "A\nx\nb\n",
+		},
+		{
+			name:   "inserts at both ends",
+			base:   "a\n",
+			ours:   "x\na\n",
+			theirs: "a\ny\n",
+			want:   "x\na\ny\n",
+		},
+		{
+			name:   "same line edited differently",
+			base:   "a\nb\nc\n",
+			ours:   "a\nours\nc\n",
+			theirs: "a\ntheirs\nc\n",
+			want:   "a\n<<<<<<< ours\nours\n=======\ntheirs\n>>>>>>> theirs\nc\n",
+			wantConflicts: []Conflict{
+				{Line: 2, Base: "b\n", Ours: "ours\n", Theirs: "theirs\n"},
+			},
+		},
+		{
+			name:   "different inserts at the same place",
+			base:   "a\nb\n",
+			ours:   "a\nx\nb\n",
+			theirs: "a\ny\nb\n",
+			want:   "a\n<<<<<<< ours\nx\n=======\ny\n>>>>>>> theirs\nb\n",
+			wantConflicts: []Conflict{
+				{Line: 2, Base: "", Ours: "x\n", Theirs: "y\n"},
+			},
+		},
+		{
+			name:   "edit overlapping two edits",
+			base:   "a\nb\nc\nd\n",
+			ours:   "A\nb\nC\nd\n",
+			theirs: "x\nd\n",
+			want:   "<<<<<<< ours\nA\nb\nC\n=======\nx\n>>>>>>> theirs\nd\n",
+			wantConflicts: []Conflict{
+				{Line: 1, Base: "a\nb\nc\n", Ours: "A\nb\nC\n", Theirs: "x\n"},
+			},
+		},
+		{
+			name:   "conflict lines count earlier markers",
+			base:   "a\nb\nc\n",
+			ours:   "A\nb\nC\n",
+			theirs: "1\nb\n3\n",
+			want: "<<<<<<< ours\nA\n=======\n1\n>>>>>>> theirs\nb\n" +
+				"<<<<<<< ours\nC\n=======\n3\n>>>>>>> theirs\n",
+			wantConflicts: []Conflict{
+				{Line: 1, Base: "a\n", Ours: "A\n", Theirs: "1\n"},
+				{Line: 7, Base: "c\n", Ours: "C\n", Theirs: "3\n"},
+			},
+		},
+		{
+			name:   "missing trailing newline kept",
+			base:   "a\nb",
+			ours:   "A\nb",
+			theirs: "a\nb",
+			want:   "A\nb",
+		},
+		{
+			name:   "conflict without trailing newline",
+			base:   "a",
+			ours:   "b",
+			theirs: "c",
+			want:   "<<<<<<< ours\nb\n=======\nc\n>>>>>>> theirs\n",
+			wantConflicts: []Conflict{
+				{Line: 1, Base: "a", Ours: "b", Theirs: "c"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Merge(tt.base, tt.ours, tt.theirs, "ours", "theirs")
+			if got.Text != tt.want {
+				t.Errorf("Merge text =\n%q\nwant\n%q", got.Text, tt.want)
+			}
+			if !reflect.DeepEqual(got.Conflicts, tt.wantConflicts) {
+				t.Errorf("Merge conflicts = %+v, want %+v", got.Conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
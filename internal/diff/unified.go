@@ -0,0 +1,106 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+// Unified renders the line diff of a and b in unified diff format, using the
+// labels in the --- and +++ headers. It returns an empty string when a and b
+// are equal.
+func Unified(a, b, fromLabel, toLabel string) string {
+	al, bl := SplitLines(a), SplitLines(b)
+	steps := Tokens(al, bl)
+
+	var out strings.Builder
+	for _, h := range hunks(steps) {
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromLabel, toLabel)
+		}
+
+		// Line numbers are 1-based, except that an empty range points at the
+		// line before it
+		oldStart, oldLen, newStart, newLen := -1, 0, -1, 0
+		for _, s := range steps[h[0]:h[1]] {
+			if s.A >= 0 {
+				if oldStart < 0 {
+					oldStart = s.A + 1
+				}
+				oldLen++
+			}
+			if s.B >= 0 {
+				if newStart < 0 {
+					newStart = s.B + 1
+				}
+				newLen++
+			}
+		}
+		if oldStart < 0 {
+			oldStart = linesBefore(steps[:h[0]], func(s Step) int { return s.A })
+		}
+		if newStart < 0 {
+			newStart = linesBefore(steps[:h[0]], func(s Step) int { return s.B })
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(oldStart, oldLen), hunkRange(newStart, newLen))
+
+		for _, s := range steps[h[0]:h[1]] {
+			switch s.Op {
+			case OpEqual:
+				writeLine(&out, ' ', al[s.A])
+			case OpDelete:
+				writeLine(&out, '-', al[s.A])
+			case OpInsert:
+				writeLine(&out, '+', bl[s.B])
+			}
+		}
+	}
+	return out.String()
+}
+
+// hunks returns the [start, end) step ranges of each hunk, merging changes
+// whose context would overlap.
+func hunks(steps []Step) [][2]int {
+	var out [][2]int
+	for i, s := range steps {
+		if s.Op == OpEqual {
+			continue
+		}
+		start := max(i-contextLines, 0)
+		end := min(i+1+contextLines, len(steps))
+		if len(out) > 0 && start <= out[len(out)-1][1] {
+			out[len(out)-1][1] = end
+			continue
+		}
+		out = append(out, [2]int{start, end})
+	}
+	return out
+}
+
+// linesBefore counts the lines of one side consumed by steps.
+func linesBefore(steps []Step, index func(Step) int) int {
+	n := 0
+	for _, s := range steps {
+		if index(s) >= 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func hunkRange(start, length int) string {
+	if length == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
+
+func writeLine(out *strings.Builder, prefix byte, line string) {
+	out.WriteByte(prefix)
+	out.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		out.WriteString("\n\\ No newline at end of file\n")
+	}
+}
@@ -9,6 +9,9 @@ var (
 	ErrBadRequest         = errors.New("bad request")
 	ErrUnauthorized       = errors.New("unauthorized")
 	ErrForbidden          = errors.New("forbidden")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrLocked             = errors.New("locked")
 	ErrTooManyRequests    = errors.New("too many requests")
 	ErrServiceUnavailable = errors.New("service unavailable")
 	ErrGatewayTimeout     = errors.New("gateway timeout")
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// parseAsOf reads the optional as_of query parameter, an RFC 3339 timestamp
+// of the moment to read materials at. It returns nil when the parameter is
+// absent.
+func parseAsOf(r *http.Request) (*time.Time, error) {
+	value := r.URL.Query().Get("as_of")
+	if value == "" {
+		return nil, nil
+	}
+	asOf, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid as_of %q, expected RFC 3339", value)
+	}
+	return &asOf, nil
+}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handlers) CreateMaterialDraft(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teacherID := chi.URLParam(r, "id")
+	teacherIDInt, err := strconv.ParseInt(teacherID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	materialID := chi.URLParam(r, "material_id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(teacherIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateMaterialRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	draft, err := h.svc.CreateMaterialDraft(ctx, teacherIDInt, materialIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Material not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Material has no main version to draft from", http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(draft); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) ListMaterialDrafts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teacherID := chi.URLParam(r, "id")
+	teacherIDInt, err := strconv.ParseInt(teacherID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	materialID := chi.URLParam(r, "material_id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(teacherIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	drafts, err := h.svc.ListMaterialDrafts(ctx, teacherIDInt, materialIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Material not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(drafts); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) UpdateMaterialDraft(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teacherID := chi.URLParam(r, "id")
+	teacherIDInt, err := strconv.ParseInt(teacherID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	materialID := chi.URLParam(r, "material_id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	draftID := chi.URLParam(r, "draft_id")
+	draftIDInt, err := strconv.ParseInt(draftID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(teacherIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(draftIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateMaterialRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	draft, err := h.svc.UpdateMaterialDraft(ctx, teacherIDInt, materialIDInt, draftIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Draft not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Draft was published or discarded in the meantime", http.StatusConflict)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(draft); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) PublishMaterialDraft(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teacherID := chi.URLParam(r, "id")
+	teacherIDInt, err := strconv.ParseInt(teacherID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	materialID := chi.URLParam(r, "material_id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	draftID := chi.URLParam(r, "draft_id")
+	draftIDInt, err := strconv.ParseInt(draftID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(teacherIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(draftIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Publishing replaces main, so it must not overwrite edits the draft's
+	// author has not seen; clients send the draft's base version
+	ifMatch, ok := parseIfMatch(r)
+	if !ok {
+		http.Error(w, "If-Match header is required", http.StatusPreconditionRequired)
+		return
+	}
+
+	material, err := h.svc.PublishMaterialDraft(ctx, teacherIDInt, materialIDInt, draftIDInt, ifMatch)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Draft not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Draft was published or discarded in the meantime", http.StatusConflict)
+			return
+		case errors.Is(err, customerrors.ErrPreconditionFailed):
+			http.Error(w, "Material has changed since the draft was started", http.StatusPreconditionFailed)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("ETag", materialETag(material.CurrentVersionID))
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(material); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) DeleteMaterialDraft(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teacherID := chi.URLParam(r, "id")
+	teacherIDInt, err := strconv.ParseInt(teacherID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	materialID := chi.URLParam(r, "material_id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	draftID := chi.URLParam(r, "draft_id")
+	draftIDInt, err := strconv.ParseInt(draftID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(teacherIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(draftIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.svc.DeleteMaterialDraft(ctx, teacherIDInt, materialIDInt, draftIDInt); err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Draft not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, customerrors.ErrLocked) {
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// materialETag is the entity tag of a material, derived from its current
+// version. Every change to a material moves its current version, so the tag
+// changes with it.
+func materialETag(currentVersionID int64) string {
+	return fmt.Sprintf(`"v%d"`, currentVersionID)
+}
+
+// mainVersionETag returns the material's entity tag from its version list.
+func mainVersionETag(versions []models.MaterialVersion) (string, bool) {
+	for _, v := range versions {
+		if v.IsMain {
+			return materialETag(v.ID), true
+		}
+	}
+	return "", false
+}
+
+// parseIfMatch reads the If-Match header as a list of current version IDs.
+// ok is false when the header is missing. "*" returns nil, which matches any
+// version, while tags this API never issued are dropped and so never match.
+func parseIfMatch(r *http.Request) (versionIDs []int64, ok bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return nil, false
+	}
+	if header == "*" {
+		return nil, true
+	}
+
+	versionIDs = []int64{}
+	for _, tag := range strings.Split(header, ",") {
+		// Weak tags never match under If-Match's strong comparison
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"v`) || !strings.HasSuffix(tag, `"`) || len(tag) < 4 {
+			continue
+		}
+		id, err := strconv.ParseInt(tag[2:len(tag)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		versionIDs = append(versionIDs, id)
+	}
+	return versionIDs, true
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handlers) ForkMaterial(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.ForkMaterialRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	forkID, err := h.svc.ForkMaterial(ctx, materialIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Material, teacher or subject not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Teacher is deactivated", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Teachers cannot fork their own materials", http.StatusBadRequest)
+			return
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Material has no main version to fork", http.StatusConflict)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(map[string]int64{"id": forkID}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) GetMaterialLineage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lineage, err := h.svc.GetMaterialLineage(ctx, materialIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Material not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(lineage); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) GetMaterialUpstream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	upstream, err := h.svc.GetMaterialUpstream(ctx, materialIDInt)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Material not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Material is not a fork", http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(upstream); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) SyncMaterialFork(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.SyncMaterialForkRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status := http.StatusOK
+	sync, err := h.svc.SyncMaterialFork(ctx, materialIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrConflict) && len(sync.Conflicts) > 0:
+			status = http.StatusConflict
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Fork changed during sync", http.StatusConflict)
+			return
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Material not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrForbidden):
+			http.Error(w, "Only the fork owner can sync it", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Material is not a fork", http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(sync); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
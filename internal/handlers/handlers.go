@@ -78,7 +78,18 @@ func (h *Handlers) ListSubjects(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) ListMaterials(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	materials, err := h.svc.ListMaterials(ctx)
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var materials []models.Material
+	if asOf != nil {
+		materials, err = h.svc.ListMaterialsAsOf(ctx, *asOf)
+	} else {
+		materials, err = h.svc.ListMaterials(ctx)
+	}
 	if err != nil {
 		if errors.Is(err, customerrors.ErrNotFound) {
 			http.Error(w, "Materials not found", http.StatusNotFound)
@@ -120,6 +131,9 @@ func (h *Handlers) ListMaterialVersionsByMaterialID(w http.ResponseWriter, r *ht
 		return
 	}
 
+	if etag, ok := mainVersionETag(materialVersions); ok {
+		w.Header().Set("ETag", etag)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(materialVersions); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -174,7 +188,18 @@ func (h *Handlers) GetTeacherMaterials(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	materials, err := h.svc.GetTeacherMaterials(ctx, teacherIDInt)
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var materials []models.Material
+	if asOf != nil {
+		materials, err = h.svc.GetTeacherMaterialsAsOf(ctx, teacherIDInt, *asOf)
+	} else {
+		materials, err = h.svc.GetTeacherMaterials(ctx, teacherIDInt)
+	}
 	if err != nil {
 		if errors.Is(err, customerrors.ErrNotFound) {
 			http.Error(w, "Teacher materials not found", http.StatusNotFound)
@@ -219,9 +244,17 @@ func (h *Handlers) CreateInitialTeacherMaterial(w http.ResponseWriter, r *http.R
 
 	materialID, err := h.svc.CreateInitialTeacherMaterial(ctx, teacherIDInt, req)
 	if err != nil {
-		// TODO: Handle error
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Teacher not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Teacher is deactivated", http.StatusForbidden)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -259,7 +292,18 @@ func (h *Handlers) GetTeacherMaterialByID(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	material, err := h.svc.GetTeacherMaterialByID(ctx, teacherIDInt, materialIDInt)
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var material models.Material
+	if asOf != nil {
+		material, err = h.svc.GetTeacherMaterialByIDAsOf(ctx, teacherIDInt, materialIDInt, *asOf)
+	} else {
+		material, err = h.svc.GetTeacherMaterialByID(ctx, teacherIDInt, materialIDInt)
+	}
 	if err != nil {
 		if errors.Is(err, customerrors.ErrNotFound) {
 			http.Error(w, "Material not found", http.StatusNotFound)
@@ -269,6 +313,10 @@ func (h *Handlers) GetTeacherMaterialByID(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// A past state is not something a client can update against
+	if asOf == nil {
+		w.Header().Set("ETag", materialETag(material.CurrentVersionID))
+	}
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(material); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -314,15 +362,77 @@ func (h *Handlers) UpdateTeacherMaterialByID(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	material, err := h.svc.UpdateTeacherMaterialByID(ctx, teacherIDInt, materialIDInt, req)
+	// Updates that name their base version are merged instead of requiring
+	// the material to be unchanged
+	if req.BaseVersionID != nil {
+		h.mergeTeacherMaterialUpdate(w, r, teacherIDInt, materialIDInt, req)
+		return
+	}
+
+	ifMatch, ok := parseIfMatch(r)
+	if !ok {
+		http.Error(w, "If-Match header or base_version_id is required", http.StatusPreconditionRequired)
+		return
+	}
+
+	material, err := h.svc.UpdateTeacherMaterialByID(ctx, teacherIDInt, materialIDInt, ifMatch, req)
 	if err != nil {
-		// TODO: Handle error
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Material not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrPreconditionFailed):
+			http.Error(w, "Material has changed since it was read", http.StatusPreconditionFailed)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("ETag", materialETag(material.CurrentVersionID))
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(material); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+}
 
+func (h *Handlers) mergeTeacherMaterialUpdate(w http.ResponseWriter, r *http.Request, teacherID, materialID int64, req models.UpdateMaterialRequest) {
+	merge, err := h.svc.MergeTeacherMaterialUpdate(r.Context(), teacherID, materialID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrConflict) && len(merge.Conflicts) > 0:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			if err := json.NewEncoder(w).Encode(merge); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Material or base version not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Material has no main version to merge with", http.StatusBadRequest)
+			return
+		case errors.Is(err, customerrors.ErrPreconditionFailed):
+			http.Error(w, "Material changed while merging, retry the update", http.StatusPreconditionFailed)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("ETag", materialETag(merge.Material.CurrentVersionID))
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(material); err != nil {
+	if err := json.NewEncoder(w).Encode(merge.Material); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -356,9 +466,17 @@ func (h *Handlers) DeleteTeacherMaterialByID(w http.ResponseWriter, r *http.Requ
 	}
 
 	if err = h.svc.DeleteTeacherMaterialByID(ctx, teacherIDInt, materialIDInt); err != nil {
-		// TODO: Handle error
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Material not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
 	w.WriteHeader(http.StatusNoContent)
@@ -391,12 +509,30 @@ func (h *Handlers) UpdateMaterialVersionMain(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if err = h.svc.UpdateMaterialVersionMain(ctx, materialIDInt, versionIDInt); err != nil {
-		// TODO: Handle error
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	ifMatch, ok := parseIfMatch(r)
+	if !ok {
+		http.Error(w, "If-Match header is required", http.StatusPreconditionRequired)
 		return
 	}
 
+	if err = h.svc.UpdateMaterialVersionMain(ctx, materialIDInt, versionIDInt, ifMatch); err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Material version not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrPreconditionFailed):
+			http.Error(w, "Material has changed since it was read", http.StatusPreconditionFailed)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("ETag", materialETag(versionIDInt))
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(map[string]string{"id": versionID}); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
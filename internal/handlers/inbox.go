@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handlers) GetTeacherInbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teacherID := chi.URLParam(r, "id")
+	teacherIDInt, err := strconv.ParseInt(teacherID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(teacherIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sort := r.URL.Query().Get("sort")
+	if err = h.validate.Var(sort, "omitempty,oneof=oldest newest"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	inbox, err := h.svc.GetTeacherInbox(ctx, teacherIDInt, sort == "newest")
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Teacher not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(inbox); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handlers) ListMaterialProposalComments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	proposalID := chi.URLParam(r, "id")
+	proposalIDInt, err := strconv.ParseInt(proposalID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(proposalIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	comments, err := h.svc.ListMaterialProposalComments(ctx, proposalIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Proposal not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(comments); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) CreateMaterialProposalComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	proposalID := chi.URLParam(r, "id")
+	proposalIDInt, err := strconv.ParseInt(proposalID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(proposalIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateMaterialProposalCommentRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.svc.CreateMaterialProposalComment(ctx, proposalIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Proposal, comment or teacher not found", http.StatusNotFound)
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Invalid line range or parent comment", http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(comment); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) ResolveMaterialProposalComment(w http.ResponseWriter, r *http.Request) {
+	h.setMaterialProposalCommentResolved(w, r, h.svc.ResolveMaterialProposalComment)
+}
+
+func (h *Handlers) ReopenMaterialProposalComment(w http.ResponseWriter, r *http.Request) {
+	h.setMaterialProposalCommentResolved(w, r, h.svc.ReopenMaterialProposalComment)
+}
+
+func (h *Handlers) setMaterialProposalCommentResolved(
+	w http.ResponseWriter,
+	r *http.Request,
+	set func(context.Context, int64, int64, models.ResolveMaterialProposalCommentRequest) (models.MaterialProposalComment, error),
+) {
+	ctx := r.Context()
+
+	proposalID := chi.URLParam(r, "id")
+	proposalIDInt, err := strconv.ParseInt(proposalID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	commentID := chi.URLParam(r, "comment_id")
+	commentIDInt, err := strconv.ParseInt(commentID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(proposalIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(commentIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.ResolveMaterialProposalCommentRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	comment, err := set(ctx, proposalIDInt, commentIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Comment not found", http.StatusNotFound)
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Only thread starting comments can be resolved", http.StatusBadRequest)
+		case errors.Is(err, customerrors.ErrForbidden):
+			http.Error(w, "Teacher cannot resolve this thread", http.StatusForbidden)
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Thread is already in that state", http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(comment); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
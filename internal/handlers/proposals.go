@@ -0,0 +1,412 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handlers) CreateMaterialProposal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateMaterialProposalRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proposalID, err := h.svc.CreateMaterialProposal(ctx, materialIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Material, version or teacher not found", http.StatusNotFound)
+		case errors.Is(err, customerrors.ErrForbidden):
+			http.Error(w, "Owners cannot propose changes to their own material", http.StatusForbidden)
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Teacher or material owner is deactivated", http.StatusForbidden)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(map[string]int64{"id": proposalID}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) ListMaterialProposalsByMaterialID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proposals, err := h.svc.ListMaterialProposalsByMaterialID(ctx, materialIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Material not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(proposals); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) GetMaterialProposalByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	proposalID := chi.URLParam(r, "id")
+	proposalIDInt, err := strconv.ParseInt(proposalID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(proposalIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proposal, err := h.svc.GetMaterialProposalByID(ctx, proposalIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Proposal not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(proposal); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) ApproveMaterialProposal(w http.ResponseWriter, r *http.Request) {
+	h.decideMaterialProposal(w, r, h.svc.ApproveMaterialProposal)
+}
+
+func (h *Handlers) RejectMaterialProposal(w http.ResponseWriter, r *http.Request) {
+	h.decideMaterialProposal(w, r, h.svc.RejectMaterialProposal)
+}
+
+func (h *Handlers) decideMaterialProposal(
+	w http.ResponseWriter,
+	r *http.Request,
+	decide func(context.Context, int64, models.DecideMaterialProposalRequest) (models.MaterialProposal, error),
+) {
+	ctx := r.Context()
+
+	proposalID := chi.URLParam(r, "id")
+	proposalIDInt, err := strconv.ParseInt(proposalID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(proposalIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.DecideMaterialProposalRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proposal, err := decide(ctx, proposalIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Proposal not found", http.StatusNotFound)
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Teacher or material owner is deactivated", http.StatusForbidden)
+		case errors.Is(err, customerrors.ErrForbidden):
+			http.Error(w, "Teacher may not decide this proposal under the review policy", http.StatusForbidden)
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Proposal is already decided, changed since it was read, already approved by this teacher or must be rebased first", http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(proposal); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) DiffMaterialProposal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	proposalID := chi.URLParam(r, "id")
+	proposalIDInt, err := strconv.ParseInt(proposalID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(proposalIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proposalDiff, err := h.svc.DiffMaterialProposal(ctx, proposalIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Proposal or its base version not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(proposalDiff); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) RebaseMaterialProposal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	proposalID := chi.URLParam(r, "id")
+	proposalIDInt, err := strconv.ParseInt(proposalID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(proposalIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.RebaseMaterialProposalRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status := http.StatusOK
+	rebase, err := h.svc.RebaseMaterialProposal(ctx, proposalIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrConflict) && len(rebase.Conflicts) > 0:
+			status = http.StatusConflict
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Proposal changed during rebase", http.StatusConflict)
+			return
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Proposal or its base version not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrForbidden):
+			http.Error(w, "Only the proposal author can rebase it", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Teacher or material owner is deactivated", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Only pending proposals can be rebased", http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(rebase); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) WithdrawMaterialProposal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	proposalID := chi.URLParam(r, "id")
+	proposalIDInt, err := strconv.ParseInt(proposalID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(proposalIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.WithdrawMaterialProposalRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proposal, err := h.svc.WithdrawMaterialProposal(ctx, proposalIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Proposal not found", http.StatusNotFound)
+		case errors.Is(err, customerrors.ErrForbidden):
+			http.Error(w, "Only the proposal author can withdraw it", http.StatusForbidden)
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Only pending proposals can be withdrawn", http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(proposal); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) ReviseMaterialProposal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	proposalID := chi.URLParam(r, "id")
+	proposalIDInt, err := strconv.ParseInt(proposalID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(proposalIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.ReviseMaterialProposalRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proposal, err := h.svc.ReviseMaterialProposal(ctx, proposalIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Proposal or its base version not found", http.StatusNotFound)
+		case errors.Is(err, customerrors.ErrForbidden):
+			http.Error(w, "Only the proposal author can revise it", http.StatusForbidden)
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Teacher or material owner is deactivated", http.StatusForbidden)
+		case errors.Is(err, customerrors.ErrBadRequest), errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Only pending proposals can be revised", http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(proposal); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) ListMaterialProposalRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	proposalID := chi.URLParam(r, "id")
+	proposalIDInt, err := strconv.ParseInt(proposalID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(proposalIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	revisions, err := h.svc.ListMaterialProposalRevisions(ctx, proposalIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Proposal not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(revisions); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
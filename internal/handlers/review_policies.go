@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handlers) GetMaterialReviewPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	policy, err := h.svc.GetMaterialReviewPolicy(ctx, materialIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Material not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(policy); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) SetMaterialReviewPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetMaterialReviewPolicyRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	policy, err := h.svc.SetMaterialReviewPolicy(ctx, materialIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Material not found", http.StatusNotFound)
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+		case errors.Is(err, customerrors.ErrForbidden):
+			http.Error(w, "Only the material owner can set its review policy", http.StatusForbidden)
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Unknown reviewers or too few approvers for the required approvals", http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(policy); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) GetSubjectReviewPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subjectID := chi.URLParam(r, "id")
+	subjectIDInt, err := strconv.ParseInt(subjectID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(subjectIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	policy, err := h.svc.GetSubjectReviewPolicy(ctx, subjectIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Subject not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(policy); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) SetSubjectReviewPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subjectID := chi.URLParam(r, "id")
+	subjectIDInt, err := strconv.ParseInt(subjectID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(subjectIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetSubjectReviewPolicyRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	policy, err := h.svc.SetSubjectReviewPolicy(ctx, subjectIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Subject not found", http.StatusNotFound)
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Unknown reviewers or too few approvers for the required approvals", http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(policy); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) ListMaterialProposalApprovals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	proposalID := chi.URLParam(r, "id")
+	proposalIDInt, err := strconv.ParseInt(proposalID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(proposalIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	approvals, err := h.svc.ListMaterialProposalApprovals(ctx, proposalIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Proposal not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(approvals); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
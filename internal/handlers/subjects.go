@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handlers) CreateSubject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req models.CreateSubjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	subject, err := h.svc.CreateSubject(ctx, req)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Parent subject not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(subject); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) GetSubjectByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subjectID := chi.URLParam(r, "id")
+	subjectIDInt, err := strconv.ParseInt(subjectID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(subjectIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	subject, err := h.svc.GetSubjectByID(ctx, subjectIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Subject not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(subject); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) UpdateSubject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subjectID := chi.URLParam(r, "id")
+	subjectIDInt, err := strconv.ParseInt(subjectID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(subjectIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateSubjectRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	subject, err := h.svc.UpdateSubject(ctx, subjectIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Subject or parent subject not found", http.StatusNotFound)
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "A subject cannot be moved under itself or one of its descendants", http.StatusBadRequest)
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Subject changed concurrently, retry the update", http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(subject); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteSubject deletes a subject. A subject with materials needs a
+// reassign_to query parameter naming the subject to move them to.
+func (h *Handlers) DeleteSubject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subjectID := chi.URLParam(r, "id")
+	subjectIDInt, err := strconv.ParseInt(subjectID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(subjectIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var reassignTo *int64
+	if value := r.URL.Query().Get("reassign_to"); value != "" {
+		reassignToInt, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err = h.validate.Var(reassignToInt, "required,min=1"); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reassignTo = &reassignToInt
+	}
+
+	if err = h.svc.DeleteSubject(ctx, subjectIDInt, reassignTo); err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Subject or reassignment subject not found", http.StatusNotFound)
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Materials cannot be reassigned to the subject being deleted", http.StatusBadRequest)
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Subject has child subjects, has materials and no reassign_to, or changed concurrently", http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) ListSubjectMaterials(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subjectID := chi.URLParam(r, "id")
+	subjectIDInt, err := strconv.ParseInt(subjectID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(subjectIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	materials, err := h.svc.ListSubjectMaterials(ctx, subjectIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Subject not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(materials); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// tagParam reads the tag name from the path. Tag names may contain spaces
+// and slashes, which chi leaves escaped when the request path needed raw
+// escaping.
+func tagParam(r *http.Request) string {
+	tag := chi.URLParam(r, "tag")
+	if unescaped, err := url.PathUnescape(tag); err == nil {
+		return unescaped
+	}
+	return tag
+}
+
+func (h *Handlers) ListMaterialVersionTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	versionID := chi.URLParam(r, "version_id")
+	versionIDInt, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(versionIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tags, err := h.svc.ListMaterialVersionTags(ctx, materialIDInt, versionIDInt)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Material version not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tags); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) CreateMaterialVersionTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	versionID := chi.URLParam(r, "version_id")
+	versionIDInt, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(versionIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateMaterialVersionTagRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tag, err := h.svc.CreateMaterialVersionTag(ctx, materialIDInt, versionIDInt, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrBadRequest):
+			http.Error(w, "Tag name cannot be blank", http.StatusBadRequest)
+			return
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Material version not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrForbidden):
+			http.Error(w, "Only the material owner can tag its versions", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, "Tag is already used on this material", http.StatusConflict)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(tag); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) DeleteMaterialVersionTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	versionID := chi.URLParam(r, "version_id")
+	versionIDInt, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tag := tagParam(r)
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(versionIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.DeleteMaterialVersionTagRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.svc.DeleteMaterialVersionTag(ctx, materialIDInt, versionIDInt, tag, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Tag not found", http.StatusNotFound)
+			return
+		case errors.Is(err, customerrors.ErrLocked):
+			http.Error(w, "Material owner is deactivated", http.StatusForbidden)
+			return
+		case errors.Is(err, customerrors.ErrForbidden):
+			http.Error(w, "Only the material owner can untag its versions", http.StatusForbidden)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) GetMaterialVersionByTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	materialID := chi.URLParam(r, "id")
+	materialIDInt, err := strconv.ParseInt(materialID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tag := tagParam(r)
+
+	if err = h.validate.Var(materialIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := h.svc.GetMaterialVersionByTag(ctx, materialIDInt, tag)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Tag not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(version); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handlers) CreateTeacher(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req models.CreateTeacherRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	teacher, err := h.svc.CreateTeacher(ctx, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(teacher); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) UpdateTeacher(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teacherID := chi.URLParam(r, "id")
+	teacherIDInt, err := strconv.ParseInt(teacherID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(teacherIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateTeacherRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	teacher, err := h.svc.UpdateTeacher(ctx, teacherIDInt, req)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			http.Error(w, "Teacher not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(teacher); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) DeactivateTeacher(w http.ResponseWriter, r *http.Request) {
+	h.setTeacherActive(w, r, h.svc.DeactivateTeacher, "Teacher is already deactivated")
+}
+
+func (h *Handlers) ReactivateTeacher(w http.ResponseWriter, r *http.Request) {
+	h.setTeacherActive(w, r, h.svc.ReactivateTeacher, "Teacher is already active")
+}
+
+func (h *Handlers) setTeacherActive(
+	w http.ResponseWriter,
+	r *http.Request,
+	set func(context.Context, int64) (models.Teacher, error),
+	conflictMessage string,
+) {
+	ctx := r.Context()
+
+	teacherID := chi.URLParam(r, "id")
+	teacherIDInt, err := strconv.ParseInt(teacherID, 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.validate.Var(teacherIDInt, "required,min=1"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	teacher, err := set(ctx, teacherIDInt)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrNotFound):
+			http.Error(w, "Teacher not found", http.StatusNotFound)
+		case errors.Is(err, customerrors.ErrConflict):
+			http.Error(w, conflictMessage, http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(teacher); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -31,20 +31,70 @@ func New(port string, handlers *handlers.Handlers) *Server {
 
 	// Routes (Possibly admin routes)
 	r.Get("/teachers", handlers.ListTeachers)
+	r.Post("/teachers", handlers.CreateTeacher)
 	r.Get("/subjects", handlers.ListSubjects)
+	r.Post("/subjects", handlers.CreateSubject)
+	r.Get("/subjects/{id}", handlers.GetSubjectByID)
+	r.Put("/subjects/{id}", handlers.UpdateSubject)
+	r.Delete("/subjects/{id}", handlers.DeleteSubject)
+	r.Get("/subjects/{id}/materials", handlers.ListSubjectMaterials)
+	r.Get("/subjects/{id}/review-policy", handlers.GetSubjectReviewPolicy)
+	r.Put("/subjects/{id}/review-policy", handlers.SetSubjectReviewPolicy)
 
 	// Routes (Possibly public)
 	r.Get("/materials", handlers.ListMaterials)
 	r.Get("/materials/{id}/versions", handlers.ListMaterialVersionsByMaterialID)
 	r.Put("/materials/{id}/versions/{version_id}/main", handlers.UpdateMaterialVersionMain)
+	r.Post("/materials/{id}/versions/{version_id}/revert", handlers.RevertMaterialVersion)
+	r.Get("/materials/{id}/versions/{version_id}/diff/{other_version_id}", handlers.DiffMaterialVersions)
+	r.Get("/materials/{id}/versions/{version_id}/tags", handlers.ListMaterialVersionTags)
+	r.Post("/materials/{id}/versions/{version_id}/tags", handlers.CreateMaterialVersionTag)
+	r.Delete("/materials/{id}/versions/{version_id}/tags/{tag}", handlers.DeleteMaterialVersionTag)
+	r.Get("/materials/{id}/versions/by-tag/{tag}", handlers.GetMaterialVersionByTag)
+	r.Get("/materials/{id}/blame", handlers.GetMaterialBlame)
+	r.Get("/materials/{id}/review-policy", handlers.GetMaterialReviewPolicy)
+	r.Put("/materials/{id}/review-policy", handlers.SetMaterialReviewPolicy)
+	r.Post("/materials/{id}/fork", handlers.ForkMaterial)
+	r.Get("/materials/{id}/lineage", handlers.GetMaterialLineage)
+	r.Get("/materials/{id}/upstream", handlers.GetMaterialUpstream)
+	r.Post("/materials/{id}/sync", handlers.SyncMaterialFork)
+
+	// Proposal routes
+	r.Get("/materials/{id}/proposals", handlers.ListMaterialProposalsByMaterialID)
+	r.Post("/materials/{id}/proposals", handlers.CreateMaterialProposal)
+	r.Get("/proposals/{id}", handlers.GetMaterialProposalByID)
+	r.Get("/proposals/{id}/diff", handlers.DiffMaterialProposal)
+	r.Post("/proposals/{id}/approve", handlers.ApproveMaterialProposal)
+	r.Get("/proposals/{id}/approvals", handlers.ListMaterialProposalApprovals)
+	r.Post("/proposals/{id}/reject", handlers.RejectMaterialProposal)
+	r.Post("/proposals/{id}/rebase", handlers.RebaseMaterialProposal)
+	r.Post("/proposals/{id}/withdraw", handlers.WithdrawMaterialProposal)
+	r.Get("/proposals/{id}/revisions", handlers.ListMaterialProposalRevisions)
+	r.Post("/proposals/{id}/revisions", handlers.ReviseMaterialProposal)
+	r.Get("/proposals/{id}/comments", handlers.ListMaterialProposalComments)
+	r.Post("/proposals/{id}/comments", handlers.CreateMaterialProposalComment)
+	r.Post("/proposals/{id}/comments/{comment_id}/resolve", handlers.ResolveMaterialProposalComment)
+	r.Post("/proposals/{id}/comments/{comment_id}/reopen", handlers.ReopenMaterialProposalComment)
 
 	// Teacher routes
 	r.Get("/teachers/{id}", handlers.GetTeacherByID)
+	r.Patch("/teachers/{id}", handlers.UpdateTeacher)
+	r.Post("/teachers/{id}/deactivate", handlers.DeactivateTeacher)
+	r.Post("/teachers/{id}/reactivate", handlers.ReactivateTeacher)
+	r.Get("/teachers/{id}/inbox", handlers.GetTeacherInbox)
 	r.Get("/teachers/{id}/materials", handlers.GetTeacherMaterials)
 	r.Post("/teachers/{id}/materials", handlers.CreateInitialTeacherMaterial)
 	r.Get("/teachers/{id}/materials/{material_id}", handlers.GetTeacherMaterialByID)
 	r.Put("/teachers/{id}/materials/{material_id}", handlers.UpdateTeacherMaterialByID)
 	r.Delete("/teachers/{id}/materials/{material_id}", handlers.DeleteTeacherMaterialByID)
+	r.Get("/teachers/{id}/materials/{material_id}/drafts", handlers.ListMaterialDrafts)
+	r.Post("/teachers/{id}/materials/{material_id}/drafts", handlers.CreateMaterialDraft)
+	r.Put("/teachers/{id}/materials/{material_id}/drafts/{draft_id}", handlers.UpdateMaterialDraft)
+	r.Delete("/teachers/{id}/materials/{material_id}/drafts/{draft_id}", handlers.DeleteMaterialDraft)
+	r.Post("/teachers/{id}/materials/{material_id}/drafts/{draft_id}/publish", handlers.PublishMaterialDraft)
+
+	// Report routes
+	r.Get("/reports/proposals", handlers.GetProposalReport)
 
 	// Healthcheck
 	r.Get("/ping", handlers.Ping)
@@ -3,39 +3,69 @@ package models
 
 import (
 	"time"
+
+	"github.com/didrikolofsson/materials/internal/diff"
 )
 
 type Teacher struct {
-	ID        int64     `json:"id" validate:"required"`
-	Name      string    `json:"name" validate:"required,min=1,max=255"`
-	CreatedAt time.Time `json:"created_at" validate:"required"`
+	ID            int64      `json:"id" validate:"required"`
+	Name          string     `json:"name" validate:"required,min=1,max=255"`
+	CreatedAt     time.Time  `json:"created_at" validate:"required"`
+	DeactivatedAt *time.Time `json:"deactivated_at"`
+}
+
+type CreateTeacherRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+}
+
+// UpdateTeacherRequest changes the fields that are set.
+type UpdateTeacherRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=1,max=255"`
 }
 
 type Subject struct {
-	ID        int64     `json:"id" validate:"required"`
-	Name      string    `json:"name" validate:"required,min=1,max=255"`
-	CreatedAt time.Time `json:"created_at" validate:"required"`
+	ID              int64     `json:"id" validate:"required"`
+	Name            string    `json:"name" validate:"required,min=1,max=255"`
+	CreatedAt       time.Time `json:"created_at" validate:"required"`
+	ParentSubjectID *int64    `json:"parent_subject_id" validate:"omitempty,min=1"`
+}
+
+type CreateSubjectRequest struct {
+	Name            string `json:"name" validate:"required,min=1,max=255"`
+	ParentSubjectID *int64 `json:"parent_subject_id" validate:"omitempty,min=1"`
+}
+
+// UpdateSubjectRequest replaces the subject's name and parent. A nil
+// ParentSubjectID moves the subject to the top level.
+type UpdateSubjectRequest struct {
+	Name            string `json:"name" validate:"required,min=1,max=255"`
+	ParentSubjectID *int64 `json:"parent_subject_id" validate:"omitempty,min=1"`
 }
 
 type Material struct {
-	ID          int64     `json:"id" validate:"required"`
-	TeacherName string    `json:"teacher_name" validate:"required,min=1,max=255"`
-	SubjectName string    `json:"subject_name" validate:"required,min=1,max=255"`
-	CreatedAt   time.Time `json:"created_at" validate:"required"`
-	Title       string    `json:"title" validate:"required,min=1,max=255"`
-	Description *string   `json:"description" validate:"omitempty,min=1,max=1000"`
-	Summary     *string   `json:"summary" validate:"omitempty,min=1,max=255"`
+	ID               int64     `json:"id" validate:"required"`
+	TeacherName      string    `json:"teacher_name" validate:"required,min=1,max=255"`
+	SubjectName      string    `json:"subject_name" validate:"required,min=1,max=255"`
+	CreatedAt        time.Time `json:"created_at" validate:"required"`
+	Title            string    `json:"title" validate:"required,min=1,max=255"`
+	Description      *string   `json:"description" validate:"omitempty,min=1,max=1000"`
+	Summary          *string   `json:"summary" validate:"omitempty,min=1,max=255"`
+	CurrentVersionID int64     `json:"current_version_id"`
 }
 
 type MaterialVersion struct {
-	ID            int64     `json:"id" validate:"required"`
-	Title         string    `json:"title" validate:"required,min=1,max=255"`
-	Description   *string   `json:"description" validate:"omitempty,min=1,max=1000"`
-	Summary       *string   `json:"summary" validate:"omitempty,min=1,max=255"`
-	Content       string    `json:"content" validate:"required,min=1"`
-	VersionNumber int       `json:"version_number" validate:"required,min=1"`
-	IsMain        bool      `json:"is_main" validate:"required"`
-	CreatedAt     time.Time `json:"created_at" validate:"required"`
+	ID              int64     `json:"id" validate:"required"`
+	Title           string    `json:"title" validate:"required,min=1,max=255"`
+	Description     *string   `json:"description" validate:"omitempty,min=1,max=1000"`
+	Summary         *string   `json:"summary" validate:"omitempty,min=1,max=255"`
+	Content         string    `json:"content" validate:"required,min=1"`
+	VersionNumber   int       `json:"version_number" validate:"required,min=1"`
+	IsMain          bool      `json:"is_main" validate:"required"`
+	CreatedAt       time.Time `json:"created_at" validate:"required"`
+	ChangeNote      *string   `json:"change_note"`
+	AuthorTeacherID *int64    `json:"author_teacher_id"`
+	IsDraft         bool      `json:"is_draft"`
+	BaseVersionID   *int64    `json:"base_version_id"`
 }
 
 type CreateMaterialRequest struct {
@@ -44,11 +74,349 @@ type CreateMaterialRequest struct {
 	Summary     *string `json:"summary" validate:"omitempty,min=1,max=255"`
 	Description *string `json:"description" validate:"omitempty,min=1,max=1000"`
 	Content     string  `json:"content" validate:"required,min=1"`
+	ChangeNote  *string `json:"change_note" validate:"omitempty,min=1,max=255"`
 }
 
+// UpdateMaterialRequest changes the fields that are set. When BaseVersionID
+// is set the changes are made relative to that version and merged with
+// anything that became main since.
 type UpdateMaterialRequest struct {
-	Title       *string `json:"title" validate:"omitempty,min=1,max=255"`
-	Summary     *string `json:"summary" validate:"omitempty,min=1,max=255"`
-	Description *string `json:"description" validate:"omitempty,min=1,max=1000"`
-	Content     *string `json:"content" validate:"omitempty,min=1"`
+	Title         *string `json:"title" validate:"omitempty,min=1,max=255"`
+	Summary       *string `json:"summary" validate:"omitempty,min=1,max=255"`
+	Description   *string `json:"description" validate:"omitempty,min=1,max=1000"`
+	Content       *string `json:"content" validate:"omitempty,min=1"`
+	BaseVersionID *int64  `json:"base_version_id" validate:"omitempty,min=1"`
+	ChangeNote    *string `json:"change_note" validate:"omitempty,min=1,max=255"`
+}
+
+type MaterialProposal struct {
+	ID                     int64      `json:"id" validate:"required"`
+	MaterialID             int64      `json:"material_id" validate:"required"`
+	MaterialVersionID      int64      `json:"material_version_id" validate:"required"`
+	OwnerTeacherID         int64      `json:"owner_teacher_id" validate:"required"`
+	AuthorTeacherID        int64      `json:"author_teacher_id" validate:"required"`
+	Title                  string     `json:"title" validate:"required,min=1,max=255"`
+	Summary                *string    `json:"summary" validate:"omitempty,min=1,max=255"`
+	Description            *string    `json:"description" validate:"omitempty,min=1,max=1000"`
+	Content                string     `json:"content" validate:"required,min=1"`
+	Status                 string     `json:"status" validate:"required"`
+	DecidedByTeacherID     *int64     `json:"decided_by_teacher_id"`
+	DecidedAt              *time.Time `json:"decided_at"`
+	CreatedAt              time.Time  `json:"created_at" validate:"required"`
+	SupersededByProposalID *int64     `json:"superseded_by_proposal_id"`
+	RemindedAt             *time.Time `json:"reminded_at"`
+	Stale                  bool       `json:"stale"`
+}
+
+type CreateMaterialProposalRequest struct {
+	AuthorTeacherID   int64   `json:"author_teacher_id" validate:"required,min=1"`
+	MaterialVersionID int64   `json:"material_version_id" validate:"required,min=1"`
+	Title             string  `json:"title" validate:"required,min=1,max=255"`
+	Summary           *string `json:"summary" validate:"omitempty,min=1,max=255"`
+	Description       *string `json:"description" validate:"omitempty,min=1,max=1000"`
+	Content           string  `json:"content" validate:"required,min=1"`
+}
+
+type DecideMaterialProposalRequest struct {
+	TeacherID int64 `json:"teacher_id" validate:"required,min=1"`
+}
+
+type FieldDiff struct {
+	Changed bool    `json:"changed"`
+	From    *string `json:"from"`
+	To      *string `json:"to"`
+}
+
+type ContentDiff struct {
+	Changed bool        `json:"changed"`
+	Lines   []diff.Edit `json:"lines"`
+	Words   []diff.Edit `json:"words"`
+}
+
+type MaterialDiff struct {
+	Title       FieldDiff   `json:"title"`
+	Summary     FieldDiff   `json:"summary"`
+	Description FieldDiff   `json:"description"`
+	Content     ContentDiff `json:"content"`
+}
+
+type MaterialProposalDiff struct {
+	ProposalID    int64        `json:"proposal_id"`
+	BaseVersionID int64        `json:"base_version_id"`
+	MainVersionID int64        `json:"main_version_id"`
+	Base          MaterialDiff `json:"base"`
+	Main          MaterialDiff `json:"main"`
+}
+
+type RevertMaterialVersionRequest struct {
+	TeacherID int64 `json:"teacher_id" validate:"required,min=1"`
+}
+
+// MaterialMerge is the outcome of merging an update made against an older
+// version with the material's current main version. Material is set when
+// the merge succeeded; otherwise Conflicts and MergedContent describe what
+// needs resolving.
+type MaterialMerge struct {
+	Material      *Material       `json:"material,omitempty"`
+	BaseVersionID int64           `json:"base_version_id"`
+	MainVersionID int64           `json:"main_version_id"`
+	Conflicts     []MergeConflict `json:"conflicts"`
+	MergedContent *string         `json:"merged_content,omitempty"`
+}
+
+// MaterialVersionDiff describes the changes between two versions of the
+// same material. Patch holds the same changes as a unified diff, with one
+// file section per changed field.
+type MaterialVersionDiff struct {
+	MaterialID    int64        `json:"material_id"`
+	FromVersionID int64        `json:"from_version_id"`
+	ToVersionID   int64        `json:"to_version_id"`
+	Changes       MaterialDiff `json:"changes"`
+	Patch         string       `json:"patch"`
+}
+
+// MaterialBlameLine is a line of a material's content together with the
+// version that last changed it.
+type MaterialBlameLine struct {
+	LineNumber      int       `json:"line_number"`
+	Text            string    `json:"text"`
+	VersionID       int64     `json:"version_id"`
+	VersionNumber   int       `json:"version_number"`
+	AuthorTeacherID *int64    `json:"author_teacher_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// MaterialBlame annotates the content of a material's main version line by
+// line.
+type MaterialBlame struct {
+	MaterialID    int64               `json:"material_id"`
+	VersionID     int64               `json:"version_id"`
+	VersionNumber int                 `json:"version_number"`
+	Lines         []MaterialBlameLine `json:"lines"`
+}
+
+type WithdrawMaterialProposalRequest struct {
+	TeacherID int64 `json:"teacher_id" validate:"required,min=1"`
+}
+
+type ReviseMaterialProposalRequest struct {
+	AuthorTeacherID int64   `json:"author_teacher_id" validate:"required,min=1"`
+	Title           *string `json:"title" validate:"omitempty,min=1,max=255"`
+	Summary         *string `json:"summary" validate:"omitempty,min=1,max=255"`
+	Description     *string `json:"description" validate:"omitempty,min=1,max=1000"`
+	Content         *string `json:"content" validate:"omitempty,min=1"`
+}
+
+type MaterialProposalRevision struct {
+	ID                int64     `json:"id" validate:"required"`
+	ProposalID        int64     `json:"proposal_id" validate:"required"`
+	RevisionNumber    int       `json:"revision_number" validate:"required,min=1"`
+	MaterialVersionID int64     `json:"material_version_id" validate:"required"`
+	Title             string    `json:"title" validate:"required,min=1,max=255"`
+	Summary           *string   `json:"summary" validate:"omitempty,min=1,max=255"`
+	Description       *string   `json:"description" validate:"omitempty,min=1,max=1000"`
+	Content           string    `json:"content" validate:"required,min=1"`
+	CreatedAt         time.Time `json:"created_at" validate:"required"`
+}
+
+type RebaseMaterialProposalRequest struct {
+	TeacherID int64 `json:"teacher_id" validate:"required,min=1"`
+}
+
+// MergeConflict describes a field both sides of a merge changed differently.
+// For content, Line points at the conflict markers in the merged content.
+type MergeConflict struct {
+	Field  string  `json:"field"`
+	Line   int     `json:"line,omitempty"`
+	Base   *string `json:"base"`
+	Ours   *string `json:"ours"`
+	Theirs *string `json:"theirs"`
+}
+
+type MaterialProposalRebase struct {
+	Proposal      MaterialProposal `json:"proposal"`
+	Conflicts     []MergeConflict  `json:"conflicts"`
+	MergedContent *string          `json:"merged_content,omitempty"`
+}
+
+// MaterialProposalComment is a review comment on a proposal. Top level comments
+// start a thread and may be anchored to a line range of the proposed content;
+// replies are listed under their thread.
+type MaterialProposalComment struct {
+	ID                  int64                     `json:"id" validate:"required"`
+	ProposalID          int64                     `json:"proposal_id" validate:"required"`
+	AuthorTeacherID     int64                     `json:"author_teacher_id" validate:"required"`
+	ParentCommentID     *int64                    `json:"parent_comment_id"`
+	LineStart           *int                      `json:"line_start"`
+	LineEnd             *int                      `json:"line_end"`
+	Body                string                    `json:"body" validate:"required,min=1"`
+	ResolvedByTeacherID *int64                    `json:"resolved_by_teacher_id"`
+	ResolvedAt          *time.Time                `json:"resolved_at"`
+	CreatedAt           time.Time                 `json:"created_at" validate:"required"`
+	Replies             []MaterialProposalComment `json:"replies,omitempty"`
+}
+
+type CreateMaterialProposalCommentRequest struct {
+	AuthorTeacherID int64  `json:"author_teacher_id" validate:"required,min=1"`
+	ParentCommentID *int64 `json:"parent_comment_id" validate:"omitempty,min=1"`
+	LineStart       *int   `json:"line_start" validate:"required_with=LineEnd,omitempty,min=1"`
+	LineEnd         *int   `json:"line_end" validate:"required_with=LineStart,omitempty,min=1"`
+	Body            string `json:"body" validate:"required,min=1,max=5000"`
+}
+
+type ResolveMaterialProposalCommentRequest struct {
+	TeacherID int64 `json:"teacher_id" validate:"required,min=1"`
+}
+
+type InboxSection struct {
+	Counts    map[string]int64   `json:"counts"`
+	Proposals []MaterialProposal `json:"proposals"`
+}
+
+// TeacherInbox lists the pending proposals a teacher has to review on the
+// materials they own and the proposals they have authored themselves.
+type TeacherInbox struct {
+	TeacherID int64        `json:"teacher_id"`
+	Review    InboxSection `json:"review"`
+	Authored  InboxSection `json:"authored"`
+}
+
+// ReviewPolicy decides who may approve proposals on a material and how many
+// approvals are needed before a proposal is merged. Source is "material",
+// "subject" or "default" depending on where the policy comes from.
+type ReviewPolicy struct {
+	Source              string  `json:"source"`
+	MaterialID          *int64  `json:"material_id"`
+	SubjectID           *int64  `json:"subject_id"`
+	RequiredApprovals   int     `json:"required_approvals"`
+	OwnerCanSelfApprove bool    `json:"owner_can_self_approve"`
+	ReviewerTeacherIDs  []int64 `json:"reviewer_teacher_ids"`
+}
+
+type SetMaterialReviewPolicyRequest struct {
+	TeacherID           int64   `json:"teacher_id" validate:"required,min=1"`
+	RequiredApprovals   int     `json:"required_approvals" validate:"required,min=1,max=10"`
+	OwnerCanSelfApprove bool    `json:"owner_can_self_approve"`
+	ReviewerTeacherIDs  []int64 `json:"reviewer_teacher_ids" validate:"unique,dive,min=1"`
+}
+
+type SetSubjectReviewPolicyRequest struct {
+	RequiredApprovals   int     `json:"required_approvals" validate:"required,min=1,max=10"`
+	OwnerCanSelfApprove bool    `json:"owner_can_self_approve"`
+	ReviewerTeacherIDs  []int64 `json:"reviewer_teacher_ids" validate:"unique,dive,min=1"`
+}
+
+type MaterialProposalApproval struct {
+	TeacherID int64     `json:"teacher_id" validate:"required"`
+	CreatedAt time.Time `json:"created_at" validate:"required"`
+}
+
+type MaterialProposalApprovals struct {
+	ProposalID int64                      `json:"proposal_id"`
+	Policy     ReviewPolicy               `json:"policy"`
+	Approvals  []MaterialProposalApproval `json:"approvals"`
+}
+
+// ProposalStats counts proposals opened in a period and how they were
+// decided. The median time to decision only covers approved and rejected
+// proposals and is null when there are none.
+type ProposalStats struct {
+	Opened                      int64  `json:"opened"`
+	Approved                    int64  `json:"approved"`
+	Rejected                    int64  `json:"rejected"`
+	MedianTimeToDecisionSeconds *int64 `json:"median_time_to_decision_seconds"`
+}
+
+type TeacherProposalStats struct {
+	TeacherID   int64  `json:"teacher_id"`
+	TeacherName string `json:"teacher_name"`
+	ProposalStats
+}
+
+type SubjectProposalStats struct {
+	SubjectID   *int64  `json:"subject_id"`
+	SubjectName *string `json:"subject_name"`
+	ProposalStats
+}
+
+type ProposalReport struct {
+	From     *time.Time             `json:"from"`
+	To       *time.Time             `json:"to"`
+	Teachers []TeacherProposalStats `json:"teachers"`
+	Subjects []SubjectProposalStats `json:"subjects"`
+}
+
+type ForkMaterialRequest struct {
+	TeacherID int64  `json:"teacher_id" validate:"required,min=1"`
+	SubjectID *int64 `json:"subject_id" validate:"omitempty,min=1"`
+}
+
+type MaterialLineageNode struct {
+	ID                 int64                 `json:"id"`
+	TeacherID          int64                 `json:"teacher_id"`
+	TeacherName        string                `json:"teacher_name"`
+	OriginalMaterialID *int64                `json:"original_material_id"`
+	Title              *string               `json:"title"`
+	CreatedAt          time.Time             `json:"created_at"`
+	Forks              []MaterialLineageNode `json:"forks,omitempty"`
+}
+
+// MaterialLineage is the fork tree around a material. Ancestors run from
+// the material's original up to the root, and Forks holds its descendants.
+type MaterialLineage struct {
+	MaterialID int64                 `json:"material_id"`
+	Ancestors  []MaterialLineageNode `json:"ancestors"`
+	Forks      []MaterialLineageNode `json:"forks"`
+}
+
+// MaterialUpstream tells how far a fork is behind the material it was forked
+// from. Synced is the upstream version the fork last took changes from and
+// Latest is the upstream's current main version.
+type MaterialUpstream struct {
+	MaterialID          int64  `json:"material_id"`
+	OriginalMaterialID  int64  `json:"original_material_id"`
+	SyncedVersionID     *int64 `json:"synced_version_id"`
+	SyncedVersionNumber *int   `json:"synced_version_number"`
+	LatestVersionID     *int64 `json:"latest_version_id"`
+	LatestVersionNumber *int   `json:"latest_version_number"`
+	BehindBy            int64  `json:"behind_by"`
+}
+
+type SyncMaterialForkRequest struct {
+	TeacherID int64 `json:"teacher_id" validate:"required,min=1"`
+}
+
+// MaterialForkSync is the outcome of pulling upstream changes into a fork.
+// VersionID is set when the merge produced a new fork version.
+type MaterialForkSync struct {
+	Upstream      MaterialUpstream `json:"upstream"`
+	VersionID     *int64           `json:"version_id"`
+	Conflicts     []MergeConflict  `json:"conflicts"`
+	MergedContent *string          `json:"merged_content,omitempty"`
+}
+
+type MaterialVersionTag struct {
+	Name      string    `json:"name"`
+	VersionID int64     `json:"version_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateMaterialVersionTagRequest struct {
+	TeacherID int64  `json:"teacher_id" validate:"required,min=1"`
+	Name      string `json:"name" validate:"required,min=1,max=100"`
+}
+
+type DeleteMaterialVersionTagRequest struct {
+	TeacherID int64 `json:"teacher_id" validate:"required,min=1"`
+}
+
+// VersionRetentionResult summarises a retention run. In a dry run
+// VersionsDeleted counts the versions that would have been deleted, and no
+// contents are collected.
+type VersionRetentionResult struct {
+	DryRun          bool  `json:"dry_run"`
+	Materials       int   `json:"materials"`
+	VersionsScanned int   `json:"versions_scanned"`
+	VersionsDeleted int64 `json:"versions_deleted"`
+	ContentsDeleted int64 `json:"contents_deleted"`
 }
@@ -0,0 +1,22 @@
+-- name: CreateMaterialContent :exec
+-- Storing a body that is already there leaves the existing row untouched
+INSERT INTO material_contents (hash, body)
+VALUES (?, ?) ON DUPLICATE KEY
+UPDATE hash = hash;
+-- name: DeleteOrphanedMaterialContents :execresult
+DELETE FROM material_contents
+WHERE NOT EXISTS (
+		SELECT 1
+		FROM material_versions mv
+		WHERE mv.content_hash = material_contents.hash
+	)
+	AND NOT EXISTS (
+		SELECT 1
+		FROM material_proposals p
+		WHERE p.content_hash = material_contents.hash
+	)
+	AND NOT EXISTS (
+		SELECT 1
+		FROM material_proposal_revisions r
+		WHERE r.content_hash = material_contents.hash
+	);
@@ -0,0 +1,50 @@
+-- name: CreateMaterialProposalComment :execresult
+INSERT INTO material_proposal_comments (
+		proposal_id,
+		author_teacher_id,
+		parent_comment_id,
+		line_start,
+		line_end,
+		body
+	)
+VALUES (?, ?, ?, ?, ?, ?);
+-- name: GetMaterialProposalCommentByID :one
+SELECT id,
+	proposal_id,
+	author_teacher_id,
+	parent_comment_id,
+	line_start,
+	line_end,
+	body,
+	resolved_by_teacher_id,
+	resolved_at,
+	created_at
+FROM material_proposal_comments
+WHERE id = ?;
+-- name: ListMaterialProposalCommentsByProposalID :many
+SELECT id,
+	proposal_id,
+	author_teacher_id,
+	parent_comment_id,
+	line_start,
+	line_end,
+	body,
+	resolved_by_teacher_id,
+	resolved_at,
+	created_at
+FROM material_proposal_comments
+WHERE proposal_id = ?
+ORDER BY created_at ASC,
+	id ASC;
+-- name: ResolveMaterialProposalComment :execresult
+UPDATE material_proposal_comments
+SET resolved_by_teacher_id = ?,
+	resolved_at = CURRENT_TIMESTAMP
+WHERE id = ?
+	AND resolved_at IS NULL;
+-- name: ReopenMaterialProposalComment :execresult
+UPDATE material_proposal_comments
+SET resolved_by_teacher_id = NULL,
+	resolved_at = NULL
+WHERE id = ?
+	AND resolved_at IS NOT NULL;
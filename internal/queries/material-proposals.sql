@@ -0,0 +1,236 @@
+-- name: CreateMaterialProposal :execresult
+INSERT INTO material_proposals (
+		material_id,
+		material_version_id,
+		owner_teacher_id,
+		author_teacher_id,
+		title,
+		summary,
+		description,
+		content_hash
+	)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+-- name: GetMaterialProposalByID :one
+SELECT mp.id,
+	mp.material_id,
+	mp.material_version_id,
+	mp.owner_teacher_id,
+	mp.author_teacher_id,
+	mp.title,
+	mp.summary,
+	mp.description,
+	mc.body AS content,
+	mp.status,
+	mp.decided_by_teacher_id,
+	mp.decided_at,
+	mp.created_at,
+	mp.superseded_by_proposal_id,
+	mp.reminded_at,
+	m.current_version_id
+FROM material_proposals mp
+	INNER JOIN material_contents mc ON mc.hash = mp.content_hash
+	INNER JOIN materials m ON m.id = mp.material_id
+WHERE mp.id = ?;
+-- name: GetMaterialProposalForUpdate :one
+SELECT mp.id,
+	mp.material_id,
+	mp.material_version_id,
+	mp.owner_teacher_id,
+	mp.author_teacher_id,
+	mp.title,
+	mp.summary,
+	mp.description,
+	mc.body AS content,
+	mp.status,
+	mp.decided_by_teacher_id,
+	mp.decided_at,
+	mp.created_at,
+	mp.superseded_by_proposal_id,
+	mp.reminded_at,
+	m.current_version_id
+FROM material_proposals mp
+	INNER JOIN material_contents mc ON mc.hash = mp.content_hash
+	INNER JOIN materials m ON m.id = mp.material_id
+WHERE mp.id = ?
+FOR UPDATE;
+-- name: ListMaterialProposalsByMaterialID :many
+SELECT mp.id,
+	mp.material_id,
+	mp.material_version_id,
+	mp.owner_teacher_id,
+	mp.author_teacher_id,
+	mp.title,
+	mp.summary,
+	mp.description,
+	mc.body AS content,
+	mp.status,
+	mp.decided_by_teacher_id,
+	mp.decided_at,
+	mp.created_at,
+	mp.superseded_by_proposal_id,
+	mp.reminded_at,
+	m.current_version_id
+FROM material_proposals mp
+	INNER JOIN material_contents mc ON mc.hash = mp.content_hash
+	INNER JOIN materials m ON m.id = mp.material_id
+WHERE mp.material_id = ?
+ORDER BY mp.created_at DESC;
+-- name: DecideMaterialProposal :execresult
+UPDATE material_proposals
+SET status = ?,
+	decided_by_teacher_id = ?,
+	decided_at = CURRENT_TIMESTAMP
+WHERE id = ?
+	AND status = "PENDING";
+-- name: UpdateMaterialProposalContent :execresult
+UPDATE material_proposals
+SET material_version_id = ?,
+	title = ?,
+	summary = ?,
+	description = ?,
+	content_hash = ?
+WHERE id = ?
+	AND status = "PENDING";
+-- name: SupersedeMaterialProposals :exec
+UPDATE material_proposals
+SET status = "SUPERSEDED",
+	decided_by_teacher_id = author_teacher_id,
+	decided_at = CURRENT_TIMESTAMP,
+	superseded_by_proposal_id = ?
+WHERE material_id = ?
+	AND author_teacher_id = ?
+	AND status = "PENDING"
+	AND id != ?;
+-- name: CreateMaterialProposalRevision :exec
+INSERT INTO material_proposal_revisions (
+		proposal_id,
+		revision_number,
+		material_version_id,
+		title,
+		summary,
+		description,
+		content_hash
+	)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+-- name: GetMaxMaterialProposalRevisionNumber :one
+SELECT revision_number
+FROM material_proposal_revisions
+WHERE proposal_id = ?
+ORDER BY revision_number DESC
+LIMIT 1;
+-- name: ListMaterialProposalRevisionsByProposalID :many
+SELECT r.id,
+	r.proposal_id,
+	r.revision_number,
+	r.material_version_id,
+	r.title,
+	r.summary,
+	r.description,
+	mc.body AS content,
+	r.created_at
+FROM material_proposal_revisions r
+	INNER JOIN material_contents mc ON mc.hash = r.content_hash
+WHERE r.proposal_id = ?
+ORDER BY r.revision_number DESC;
+-- name: ListPendingMaterialProposalsByOwnerTeacherID :many
+SELECT mp.id,
+	mp.material_id,
+	mp.material_version_id,
+	mp.owner_teacher_id,
+	mp.author_teacher_id,
+	mp.title,
+	mp.summary,
+	mp.description,
+	mc.body AS content,
+	mp.status,
+	mp.decided_by_teacher_id,
+	mp.decided_at,
+	mp.created_at,
+	mp.superseded_by_proposal_id,
+	mp.reminded_at,
+	m.current_version_id
+FROM material_proposals mp
+	INNER JOIN material_contents mc ON mc.hash = mp.content_hash
+	INNER JOIN materials m ON m.id = mp.material_id
+WHERE mp.owner_teacher_id = ?
+	AND mp.status = "PENDING"
+ORDER BY mp.created_at ASC,
+	mp.id ASC;
+-- name: ListMaterialProposalsByAuthorTeacherID :many
+SELECT mp.id,
+	mp.material_id,
+	mp.material_version_id,
+	mp.owner_teacher_id,
+	mp.author_teacher_id,
+	mp.title,
+	mp.summary,
+	mp.description,
+	mc.body AS content,
+	mp.status,
+	mp.decided_by_teacher_id,
+	mp.decided_at,
+	mp.created_at,
+	mp.superseded_by_proposal_id,
+	mp.reminded_at,
+	m.current_version_id
+FROM material_proposals mp
+	INNER JOIN material_contents mc ON mc.hash = mp.content_hash
+	INNER JOIN materials m ON m.id = mp.material_id
+WHERE mp.author_teacher_id = ?
+ORDER BY mp.created_at ASC,
+	mp.id ASC;
+-- name: CountMaterialProposalsByOwnerTeacherID :many
+SELECT status,
+	COUNT(*) AS count
+FROM material_proposals
+WHERE owner_teacher_id = ?
+GROUP BY status;
+-- name: CountMaterialProposalsByAuthorTeacherID :many
+SELECT status,
+	COUNT(*) AS count
+FROM material_proposals
+WHERE author_teacher_id = ?
+GROUP BY status;
+-- name: ListIdleMaterialProposals :many
+SELECT mp.id,
+	mp.material_id,
+	mp.material_version_id,
+	mp.owner_teacher_id,
+	mp.author_teacher_id,
+	mp.title,
+	mp.summary,
+	mp.description,
+	mc.body AS content,
+	mp.status,
+	mp.decided_by_teacher_id,
+	mp.decided_at,
+	mp.created_at,
+	mp.superseded_by_proposal_id,
+	mp.reminded_at,
+	m.current_version_id
+FROM material_proposals mp
+	INNER JOIN material_contents mc ON mc.hash = mp.content_hash
+	INNER JOIN materials m ON m.id = mp.material_id
+WHERE mp.status = "PENDING"
+	AND COALESCE(mp.reminded_at, mp.created_at) < ?
+ORDER BY mp.created_at ASC;
+-- name: MarkMaterialProposalReminded :exec
+UPDATE material_proposals
+SET reminded_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+-- name: ExpireMaterialProposals :execresult
+UPDATE material_proposals
+SET status = "EXPIRED",
+	decided_at = CURRENT_TIMESTAMP
+WHERE status = "PENDING"
+	AND created_at < ?;
+-- name: ClearMaterialProposalsSupersededBy :exec
+-- Supersession only links proposals of one material, so clearing it lets
+-- them be deleted in any order
+UPDATE material_proposals
+SET superseded_by_proposal_id = NULL
+WHERE material_id = ?;
+-- name: DeleteMaterialProposals :exec
+-- Revisions, approvals and comments are deleted with their proposal
+DELETE FROM material_proposals
+WHERE material_id = ?;
@@ -0,0 +1,25 @@
+-- name: CreateMaterialVersionTag :exec
+INSERT INTO material_version_tags (material_id, material_version_id, name)
+VALUES (?, ?, ?);
+-- name: GetMaterialVersionTagByName :one
+SELECT id,
+	material_id,
+	material_version_id,
+	name,
+	created_at
+FROM material_version_tags
+WHERE material_id = ?
+	AND name = ?;
+-- name: ListMaterialVersionTagsByVersionID :many
+SELECT id,
+	material_id,
+	material_version_id,
+	name,
+	created_at
+FROM material_version_tags
+WHERE material_version_id = ?
+ORDER BY name ASC;
+-- name: DeleteMaterialVersionTag :execresult
+DELETE FROM material_version_tags
+WHERE material_version_id = ?
+	AND name = ?;
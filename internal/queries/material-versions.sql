@@ -1,36 +1,62 @@
 -- name: ListAllMaterialVersions :many
-SELECT id,
-	title,
-	summary,
-	description,
-	content,
-	version_number,
-	is_main,
-	created_at
-FROM material_versions;
+SELECT mv.id,
+	mv.title,
+	mv.summary,
+	mv.description,
+	mc.body AS content,
+	mv.version_number,
+	mv.is_main,
+	mv.created_at
+FROM material_versions mv
+	INNER JOIN material_contents mc ON mc.hash = mv.content_hash;
 -- name: ListMaterialVersionsByMaterialID :many
-SELECT id,
-	title,
-	summary,
-	description,
-	content,
-	version_number,
-	is_main,
-	created_at
-FROM material_versions
-WHERE material_id = ?
-ORDER BY version_number DESC;
+SELECT mv.id,
+	mv.title,
+	mv.summary,
+	mv.description,
+	mc.body AS content,
+	mv.version_number,
+	mv.is_main,
+	mv.created_at,
+	mv.change_note,
+	mv.author_teacher_id
+FROM material_versions mv
+	INNER JOIN material_contents mc ON mc.hash = mv.content_hash
+WHERE mv.material_id = ?
+	AND mv.is_draft = FALSE
+ORDER BY mv.version_number DESC;
+-- name: GetMaterialVersionByID :one
+SELECT mv.id,
+	mv.title,
+	mv.summary,
+	mv.description,
+	mc.body AS content,
+	mv.version_number,
+	mv.is_main,
+	mv.material_id,
+	mv.created_at,
+	mv.change_note,
+	mv.is_draft,
+	mv.author_teacher_id,
+	mv.base_version_id
+FROM material_versions mv
+	INNER JOIN material_contents mc ON mc.hash = mv.content_hash
+WHERE mv.id = ?;
 -- name: CreateMaterialVersion :execresult
 INSERT INTO material_versions (
 		material_id,
 		title,
 		summary,
 		description,
-		content,
+		content_hash,
 		version_number,
-		is_main
+		is_main,
+		change_note,
+		is_draft,
+		author_teacher_id,
+		base_version_id
 	)
-VALUES (?, ?, ?, ?, ?, ?, ?);
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 -- name: UpdateMaterialVersionMain :exec
 UPDATE material_versions
 SET is_main = CASE
@@ -43,4 +69,51 @@ SELECT version_number
 FROM material_versions
 WHERE material_id = ?
 ORDER BY version_number DESC
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+-- name: CountMaterialVersionsAfter :one
+SELECT COUNT(*)
+FROM material_versions
+WHERE material_id = ?
+	AND version_number > ?
+	AND is_draft = FALSE;
+-- name: ListMaterialDraftsByMaterialID :many
+SELECT mv.id,
+	mv.title,
+	mv.summary,
+	mv.description,
+	mc.body AS content,
+	mv.version_number,
+	mv.is_main,
+	mv.material_id,
+	mv.created_at,
+	mv.change_note,
+	mv.is_draft,
+	mv.author_teacher_id,
+	mv.base_version_id
+FROM material_versions mv
+	INNER JOIN material_contents mc ON mc.hash = mv.content_hash
+WHERE mv.material_id = ?
+	AND mv.is_draft = TRUE
+ORDER BY mv.created_at DESC;
+-- name: UpdateMaterialDraft :execresult
+UPDATE material_versions
+SET title = ?,
+	summary = ?,
+	description = ?,
+	content_hash = ?,
+	change_note = ?
+WHERE id = ?
+	AND is_draft = TRUE;
+-- name: PublishMaterialDraft :execresult
+-- Publishing stamps the draft with the next version number and the publish
+-- time, so it sorts after everything published while it was being drafted
+UPDATE material_versions
+SET is_draft = FALSE,
+	version_number = ?,
+	created_at = CURRENT_TIMESTAMP
+WHERE id = ?
+	AND is_draft = TRUE;
+-- name: DeleteMaterialDraft :execresult
+DELETE FROM material_versions
+WHERE id = ?
+	AND is_draft = TRUE;
\ No newline at end of file
@@ -6,6 +6,21 @@ SELECT id,
 	current_version_id,
 	created_at
 FROM materials;
+-- name: GetMaterialByID :one
+SELECT id,
+	teacher_id,
+	subject_id,
+	original_material_id,
+	current_version_id,
+	created_at,
+	upstream_version_id
+FROM materials
+WHERE id = ?;
+-- name: GetMaterialCurrentVersionForUpdate :one
+SELECT current_version_id
+FROM materials
+WHERE id = ?
+FOR UPDATE;
 -- name: ListMaterials :many
 SELECT m.id,
 	t.name as teacher_name,
@@ -13,7 +28,8 @@ SELECT m.id,
 	m.created_at,
 	mv.title,
 	mv.description,
-	mv.summary
+	mv.summary,
+	m.current_version_id
 FROM materials m
 	INNER JOIN teachers t ON m.teacher_id = t.id
 	INNER JOIN subjects s ON m.subject_id = s.id
@@ -25,7 +41,8 @@ SELECT m.id,
 	m.created_at,
 	mv.title,
 	mv.description,
-	mv.summary
+	mv.summary,
+	m.current_version_id
 FROM materials m
 	INNER JOIN teachers t ON m.teacher_id = t.id
 	INNER JOIN subjects s ON m.subject_id = s.id
@@ -38,29 +55,181 @@ SELECT m.id,
 	m.created_at,
 	mv.title,
 	mv.description,
-	mv.summary
+	mv.summary,
+	m.current_version_id
 FROM materials m
 	INNER JOIN teachers t ON m.teacher_id = t.id
 	INNER JOIN subjects s ON m.subject_id = s.id
 	INNER JOIN material_versions mv ON m.current_version_id = mv.id
 WHERE m.teacher_id = ?
 	AND m.id = ?;
+-- name: ListMaterialsAsOf :many
+-- The version that was main at as_of stands in for the current version
+SELECT m.id,
+	t.name as teacher_name,
+	s.name as subject_name,
+	m.created_at,
+	mv.title,
+	mv.description,
+	mv.summary,
+	h.material_version_id AS current_version_id
+FROM materials m
+	INNER JOIN teachers t ON m.teacher_id = t.id
+	INNER JOIN subjects s ON m.subject_id = s.id
+	INNER JOIN material_main_versions h ON h.id = (
+		SELECT h2.id
+		FROM material_main_versions h2
+		WHERE h2.material_id = m.id
+			AND h2.became_main_at <= sqlc.arg(as_of)
+		ORDER BY h2.became_main_at DESC,
+			h2.id DESC
+		LIMIT 1
+	)
+	INNER JOIN material_versions mv ON h.material_version_id = mv.id;
+-- name: GetTeacherMaterialsAsOf :many
+SELECT m.id,
+	t.name as teacher_name,
+	s.name as subject_name,
+	m.created_at,
+	mv.title,
+	mv.description,
+	mv.summary,
+	h.material_version_id AS current_version_id
+FROM materials m
+	INNER JOIN teachers t ON m.teacher_id = t.id
+	INNER JOIN subjects s ON m.subject_id = s.id
+	INNER JOIN material_main_versions h ON h.id = (
+		SELECT h2.id
+		FROM material_main_versions h2
+		WHERE h2.material_id = m.id
+			AND h2.became_main_at <= sqlc.arg(as_of)
+		ORDER BY h2.became_main_at DESC,
+			h2.id DESC
+		LIMIT 1
+	)
+	INNER JOIN material_versions mv ON h.material_version_id = mv.id
+WHERE m.teacher_id = sqlc.arg(teacher_id);
+-- name: GetTeacherMaterialByIDAsOf :one
+SELECT m.id,
+	t.name as teacher_name,
+	s.name as subject_name,
+	m.created_at,
+	mv.title,
+	mv.description,
+	mv.summary,
+	h.material_version_id AS current_version_id
+FROM materials m
+	INNER JOIN teachers t ON m.teacher_id = t.id
+	INNER JOIN subjects s ON m.subject_id = s.id
+	INNER JOIN material_main_versions h ON h.id = (
+		SELECT h2.id
+		FROM material_main_versions h2
+		WHERE h2.material_id = m.id
+			AND h2.became_main_at <= sqlc.arg(as_of)
+		ORDER BY h2.became_main_at DESC,
+			h2.id DESC
+		LIMIT 1
+	)
+	INNER JOIN material_versions mv ON h.material_version_id = mv.id
+WHERE m.teacher_id = sqlc.arg(teacher_id)
+	AND m.id = sqlc.arg(id);
 -- name: CreateMaterial :execresult
 INSERT INTO materials (
 		teacher_id,
 		subject_id,
 		original_material_id,
-		current_version_id
+		current_version_id,
+		upstream_version_id
 	)
-VALUES (?, ?, ?, ?);
+VALUES (?, ?, ?, ?, ?);
 -- name: UpdateMaterialCurrentVersion :exec
 UPDATE materials
 SET current_version_id = ?
 WHERE id = ?;
+-- name: CreateMaterialMainVersion :exec
+INSERT INTO material_main_versions (material_id, material_version_id)
+VALUES (?, ?);
+-- name: ListMaterialMainVersionHistory :many
+SELECT mv.id,
+	mv.title,
+	mv.summary,
+	mv.description,
+	mc.body AS content,
+	mv.version_number,
+	mv.is_main,
+	mv.created_at,
+	mv.change_note,
+	mv.author_teacher_id
+FROM material_main_versions h
+	INNER JOIN material_versions mv ON mv.id = h.material_version_id
+	INNER JOIN material_contents mc ON mc.hash = mv.content_hash
+WHERE h.material_id = ?
+ORDER BY h.became_main_at ASC,
+	h.id ASC;
+-- name: DeleteMaterialMainVersions :exec
+DELETE FROM material_main_versions
+WHERE material_id = ?;
 -- name: DeleteMaterial :exec
 DELETE FROM materials
 WHERE id = ?;
 -- name: UpdateMaterialOriginalID :exec
 UPDATE materials
 SET original_material_id = ?
-WHERE id = ?;
\ No newline at end of file
+WHERE id = ?;
+-- name: UpdateMaterialUpstreamVersion :execresult
+UPDATE materials
+SET upstream_version_id = ?
+WHERE id = ?
+	AND current_version_id = ?;
+-- name: ListMaterialAncestors :many
+-- Seeded materials list themselves as their original, which is not a fork
+WITH RECURSIVE ancestors (id, depth) AS (
+	SELECT original_material_id,
+		1
+	FROM materials
+	WHERE materials.id = ?
+		AND original_material_id IS NOT NULL
+		AND original_material_id <> materials.id
+	UNION ALL
+	SELECT m.original_material_id,
+		a.depth + 1
+	FROM materials m
+		INNER JOIN ancestors a ON m.id = a.id
+	WHERE m.original_material_id IS NOT NULL
+		AND m.original_material_id <> m.id
+)
+SELECT m.id,
+	m.teacher_id,
+	t.name AS teacher_name,
+	m.original_material_id,
+	mv.title,
+	m.created_at
+FROM ancestors a
+	INNER JOIN materials m ON m.id = a.id
+	INNER JOIN teachers t ON t.id = m.teacher_id
+	LEFT JOIN material_versions mv ON mv.id = m.current_version_id
+ORDER BY a.depth ASC;
+-- name: ListMaterialDescendants :many
+WITH RECURSIVE descendants (id) AS (
+	SELECT materials.id
+	FROM materials
+	WHERE original_material_id = ?
+		AND original_material_id <> materials.id
+	UNION ALL
+	SELECT m.id
+	FROM materials m
+		INNER JOIN descendants d ON m.original_material_id = d.id
+	WHERE m.original_material_id <> m.id
+)
+SELECT m.id,
+	m.teacher_id,
+	t.name AS teacher_name,
+	m.original_material_id,
+	mv.title,
+	m.created_at
+FROM descendants d
+	INNER JOIN materials m ON m.id = d.id
+	INNER JOIN teachers t ON t.id = m.teacher_id
+	LEFT JOIN material_versions mv ON mv.id = m.current_version_id
+ORDER BY m.created_at ASC,
+	m.id ASC;
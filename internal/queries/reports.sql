@@ -0,0 +1,94 @@
+-- name: ReportMaterialProposalsByTeacher :many
+SELECT t.id AS teacher_id,
+	t.name AS teacher_name,
+	COUNT(*) AS opened,
+	CAST(SUM(p.status = "APPROVED") AS SIGNED) AS approved,
+	CAST(SUM(p.status = "REJECTED") AS SIGNED) AS rejected
+FROM material_proposals p
+	INNER JOIN teachers t ON t.id = p.author_teacher_id
+WHERE (
+		sqlc.narg(created_from) IS NULL
+		OR p.created_at >= sqlc.narg(created_from)
+	)
+	AND (
+		sqlc.narg(created_to) IS NULL
+		OR p.created_at < sqlc.narg(created_to)
+	)
+GROUP BY t.id,
+	t.name
+ORDER BY t.name ASC;
+-- name: ReportMaterialProposalMedianDecisionTimeByTeacher :many
+SELECT ranked.teacher_id,
+	CAST(AVG(ranked.seconds) AS SIGNED) AS median_seconds
+FROM (
+		SELECT p.author_teacher_id AS teacher_id,
+			TIMESTAMPDIFF(SECOND, p.created_at, p.decided_at) AS seconds,
+			ROW_NUMBER() OVER (
+				PARTITION BY p.author_teacher_id
+				ORDER BY TIMESTAMPDIFF(SECOND, p.created_at, p.decided_at)
+			) AS position,
+			COUNT(*) OVER (PARTITION BY p.author_teacher_id) AS total
+		FROM material_proposals p
+		WHERE p.status IN ("APPROVED", "REJECTED")
+			AND (
+				sqlc.narg(created_from) IS NULL
+				OR p.created_at >= sqlc.narg(created_from)
+			)
+			AND (
+				sqlc.narg(created_to) IS NULL
+				OR p.created_at < sqlc.narg(created_to)
+			)
+	) ranked
+WHERE ranked.position IN (
+		FLOOR((ranked.total + 1) / 2),
+		CEIL((ranked.total + 1) / 2)
+	)
+GROUP BY ranked.teacher_id;
+-- name: ReportMaterialProposalsBySubject :many
+SELECT m.subject_id,
+	s.name AS subject_name,
+	COUNT(*) AS opened,
+	CAST(SUM(p.status = "APPROVED") AS SIGNED) AS approved,
+	CAST(SUM(p.status = "REJECTED") AS SIGNED) AS rejected
+FROM material_proposals p
+	INNER JOIN materials m ON m.id = p.material_id
+	LEFT JOIN subjects s ON s.id = m.subject_id
+WHERE (
+		sqlc.narg(created_from) IS NULL
+		OR p.created_at >= sqlc.narg(created_from)
+	)
+	AND (
+		sqlc.narg(created_to) IS NULL
+		OR p.created_at < sqlc.narg(created_to)
+	)
+GROUP BY m.subject_id,
+	s.name
+ORDER BY s.name ASC;
+-- name: ReportMaterialProposalMedianDecisionTimeBySubject :many
+SELECT ranked.subject_id,
+	CAST(AVG(ranked.seconds) AS SIGNED) AS median_seconds
+FROM (
+		SELECT m.subject_id,
+			TIMESTAMPDIFF(SECOND, p.created_at, p.decided_at) AS seconds,
+			ROW_NUMBER() OVER (
+				PARTITION BY m.subject_id
+				ORDER BY TIMESTAMPDIFF(SECOND, p.created_at, p.decided_at)
+			) AS position,
+			COUNT(*) OVER (PARTITION BY m.subject_id) AS total
+		FROM material_proposals p
+			INNER JOIN materials m ON m.id = p.material_id
+		WHERE p.status IN ("APPROVED", "REJECTED")
+			AND (
+				sqlc.narg(created_from) IS NULL
+				OR p.created_at >= sqlc.narg(created_from)
+			)
+			AND (
+				sqlc.narg(created_to) IS NULL
+				OR p.created_at < sqlc.narg(created_to)
+			)
+	) ranked
+WHERE ranked.position IN (
+		FLOOR((ranked.total + 1) / 2),
+		CEIL((ranked.total + 1) / 2)
+	)
+GROUP BY ranked.subject_id;
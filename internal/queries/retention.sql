@@ -0,0 +1,108 @@
+-- name: ListMaterialVersionsForRetention :many
+-- Drafts are never considered for retention
+SELECT mv.id,
+	mv.material_id,
+	mv.version_number,
+	mv.is_main,
+	mv.created_at,
+	EXISTS (
+		SELECT 1
+		FROM material_version_tags t
+		WHERE t.material_version_id = mv.id
+	) AS tagged,
+	EXISTS (
+		SELECT 1
+		FROM material_proposals p
+		WHERE p.material_version_id = mv.id
+	) AS referenced_by_proposal,
+	EXISTS (
+		SELECT 1
+		FROM material_proposal_revisions r
+		WHERE r.material_version_id = mv.id
+	) AS referenced_by_revision,
+	EXISTS (
+		SELECT 1
+		FROM materials m
+		WHERE m.current_version_id = mv.id
+			OR m.upstream_version_id = mv.id
+	) AS referenced_by_material,
+	-- The version main was left at on some day, which as_of reads of that
+	-- day depend on
+	EXISTS (
+		SELECT 1
+		FROM material_main_versions h
+		WHERE h.material_version_id = mv.id
+			AND NOT EXISTS (
+				SELECT 1
+				FROM material_main_versions later
+				WHERE later.material_id = h.material_id
+					AND DATE(later.became_main_at) = DATE(h.became_main_at)
+					AND (
+						later.became_main_at > h.became_main_at
+						OR (
+							later.became_main_at = h.became_main_at
+							AND later.id > h.id
+						)
+					)
+			)
+	) AS last_main_of_day
+FROM material_versions mv
+WHERE mv.is_draft = FALSE
+ORDER BY mv.material_id ASC,
+	mv.version_number DESC;
+-- name: DeleteRetiredMaterialVersion :execresult
+-- Repeats the checks retention never relaxes, in case the version became
+-- main or was referenced after it was selected
+DELETE FROM material_versions
+WHERE material_versions.id = ?
+	AND material_versions.is_main = FALSE
+	AND material_versions.is_draft = FALSE
+	AND NOT EXISTS (
+		SELECT 1
+		FROM material_proposals p
+		WHERE p.material_version_id = material_versions.id
+	)
+	AND NOT EXISTS (
+		SELECT 1
+		FROM material_proposal_revisions r
+		WHERE r.material_version_id = material_versions.id
+	)
+	AND NOT EXISTS (
+		SELECT 1
+		FROM materials m
+		WHERE m.current_version_id = material_versions.id
+			OR m.upstream_version_id = material_versions.id
+	)
+	AND NOT EXISTS (
+		SELECT 1
+		FROM material_main_versions h
+		WHERE h.material_version_id = material_versions.id
+	);
+-- name: DeleteRetiredMaterialMainVersions :execrows
+-- Removes the history rows of a retired version that were not the last
+-- of their day; if one was, the version's delete fails its check instead.
+-- DISTINCT keeps MySQL from merging the derived table, which it refuses
+-- for the table being deleted from
+DELETE FROM material_main_versions
+WHERE material_version_id = sqlc.arg(material_version_id)
+	AND id NOT IN (
+		SELECT last_of_day.id
+		FROM (
+			SELECT DISTINCT h.id
+			FROM material_main_versions h
+			WHERE h.material_version_id = sqlc.arg(material_version_id)
+				AND NOT EXISTS (
+					SELECT 1
+					FROM material_main_versions later
+					WHERE later.material_id = h.material_id
+						AND DATE(later.became_main_at) = DATE(h.became_main_at)
+						AND (
+							later.became_main_at > h.became_main_at
+							OR (
+								later.became_main_at = h.became_main_at
+								AND later.id > h.id
+							)
+						)
+				)
+		) AS last_of_day
+	);
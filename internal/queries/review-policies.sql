@@ -0,0 +1,64 @@
+-- name: GetReviewPolicyByMaterialID :one
+SELECT id,
+	material_id,
+	subject_id,
+	required_approvals,
+	owner_can_self_approve,
+	created_at
+FROM review_policies
+WHERE material_id = ?;
+-- name: GetReviewPolicyBySubjectID :one
+SELECT id,
+	material_id,
+	subject_id,
+	required_approvals,
+	owner_can_self_approve,
+	created_at
+FROM review_policies
+WHERE subject_id = ?;
+-- name: UpsertMaterialReviewPolicy :exec
+INSERT INTO review_policies (
+		material_id,
+		required_approvals,
+		owner_can_self_approve
+	)
+VALUES (?, ?, ?) ON DUPLICATE KEY
+UPDATE required_approvals = VALUES(required_approvals),
+	owner_can_self_approve = VALUES(owner_can_self_approve);
+-- name: UpsertSubjectReviewPolicy :exec
+INSERT INTO review_policies (
+		subject_id,
+		required_approvals,
+		owner_can_self_approve
+	)
+VALUES (?, ?, ?) ON DUPLICATE KEY
+UPDATE required_approvals = VALUES(required_approvals),
+	owner_can_self_approve = VALUES(owner_can_self_approve);
+-- name: DeleteReviewPolicyReviewers :exec
+DELETE FROM review_policy_reviewers
+WHERE policy_id = ?;
+-- name: CreateReviewPolicyReviewer :exec
+INSERT INTO review_policy_reviewers (policy_id, teacher_id)
+VALUES (?, ?);
+-- name: ListReviewPolicyReviewers :many
+SELECT teacher_id
+FROM review_policy_reviewers
+WHERE policy_id = ?
+ORDER BY teacher_id ASC;
+-- name: CreateMaterialProposalApproval :exec
+INSERT INTO material_proposal_approvals (proposal_id, teacher_id)
+VALUES (?, ?);
+-- name: ListMaterialProposalApprovals :many
+SELECT proposal_id,
+	teacher_id,
+	created_at
+FROM material_proposal_approvals
+WHERE proposal_id = ?
+ORDER BY created_at ASC;
+-- name: CountMaterialProposalApprovals :one
+SELECT COUNT(*)
+FROM material_proposal_approvals
+WHERE proposal_id = ?;
+-- name: DeleteMaterialProposalApprovals :exec
+DELETE FROM material_proposal_approvals
+WHERE proposal_id = ?;
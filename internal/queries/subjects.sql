@@ -1,8 +1,83 @@
 -- name: ListSubjects :many
 SELECT id,
 	name,
-	created_at
+	created_at,
+	parent_subject_id
 FROM subjects;
 -- name: SeedSubjects :exec
 INSERT INTO subjects (name)
-VALUES (?);
\ No newline at end of file
+VALUES (?);
+-- name: GetSubjectByID :one
+SELECT id,
+	name,
+	created_at,
+	parent_subject_id
+FROM subjects
+WHERE id = ?;
+-- name: GetSubjectForUpdate :one
+SELECT id,
+	name,
+	created_at,
+	parent_subject_id
+FROM subjects
+WHERE id = ?
+FOR UPDATE;
+-- name: CreateSubject :execresult
+INSERT INTO subjects (name, parent_subject_id)
+VALUES (?, ?);
+-- name: UpdateSubject :exec
+UPDATE subjects
+SET name = ?,
+	parent_subject_id = ?
+WHERE id = ?;
+-- name: DeleteSubject :exec
+DELETE FROM subjects
+WHERE id = ?;
+-- name: ListSubjectDescendantIDs :many
+WITH RECURSIVE descendants (id) AS (
+	SELECT subjects.id
+	FROM subjects
+	WHERE parent_subject_id = ?
+	UNION ALL
+	SELECT s.id
+	FROM subjects s
+		INNER JOIN descendants d ON s.parent_subject_id = d.id
+)
+SELECT id
+FROM descendants;
+-- name: CountSubjectChildren :one
+SELECT COUNT(*)
+FROM subjects
+WHERE parent_subject_id = ?;
+-- name: CountSubjectMaterials :one
+SELECT COUNT(*)
+FROM materials
+WHERE subject_id = ?;
+-- name: ReassignSubjectMaterials :exec
+UPDATE materials
+SET subject_id = sqlc.arg(to_subject_id)
+WHERE subject_id = sqlc.arg(from_subject_id);
+-- name: ListSubjectMaterials :many
+-- Materials filed under the subject or any subject below it
+WITH RECURSIVE subject_tree (id) AS (
+	SELECT subjects.id
+	FROM subjects
+	WHERE subjects.id = ?
+	UNION ALL
+	SELECT s.id
+	FROM subjects s
+		INNER JOIN subject_tree st ON s.parent_subject_id = st.id
+)
+SELECT m.id,
+	t.name as teacher_name,
+	s.name as subject_name,
+	m.created_at,
+	mv.title,
+	mv.description,
+	mv.summary,
+	m.current_version_id
+FROM materials m
+	INNER JOIN subject_tree st ON m.subject_id = st.id
+	INNER JOIN teachers t ON m.teacher_id = t.id
+	INNER JOIN subjects s ON m.subject_id = s.id
+	INNER JOIN material_versions mv ON m.current_version_id = mv.id;
@@ -1,14 +1,33 @@
 -- name: ListTeachers :many
 SELECT id,
 	name,
-	created_at
+	created_at,
+	deactivated_at
 FROM teachers;
 -- name: GetTeacherByID :one
 SELECT id,
 	name,
-	created_at
+	created_at,
+	deactivated_at
 FROM teachers
 WHERE id = ?;
 -- name: SeedTeachers :exec
 INSERT INTO teachers (name)
-VALUES (?);
\ No newline at end of file
+VALUES (?);
+-- name: CreateTeacher :execresult
+INSERT INTO teachers (name)
+VALUES (?);
+-- name: UpdateTeacherName :exec
+UPDATE teachers
+SET name = ?
+WHERE id = ?;
+-- name: DeactivateTeacher :execresult
+UPDATE teachers
+SET deactivated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+	AND deactivated_at IS NULL;
+-- name: ReactivateTeacher :execresult
+UPDATE teachers
+SET deactivated_at = NULL
+WHERE id = ?
+	AND deactivated_at IS NOT NULL;
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+)
+
+// putMaterialContent stores body in the content-addressed content table and
+// returns the hash that versions, proposals and revisions reference it by.
+// A body that is already stored is reused. Call it in the transaction that
+// inserts the referencing row: storing the body locks its row until commit,
+// so it cannot be collected as an orphan in between.
+func putMaterialContent(ctx context.Context, qtx *queries.Queries, body string) (string, error) {
+	sum := sha256.Sum256([]byte(body))
+	hash := hex.EncodeToString(sum[:])
+
+	err := qtx.CreateMaterialContent(ctx, queries.CreateMaterialContentParams{
+		Hash: hash,
+		Body: body,
+	})
+	if err != nil {
+		return "", customerrors.ErrInternal
+	}
+	return hash, nil
+}
+
+// DeleteOrphanedMaterialContents deletes the stored bodies that no version,
+// proposal or revision references any more and returns how many there were.
+func (r *MySQLRepository) DeleteOrphanedMaterialContents(ctx context.Context) (int64, error) {
+	result, err := r.q.DeleteOrphanedMaterialContents(ctx)
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return deleted, nil
+}
@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+)
+
+// CreateMaterialDraft saves a version that is neither main nor listed with
+// the published versions, started from baseVersionID. It takes the next
+// version number so it cannot collide with versions published before it.
+func (r *MySQLRepository) CreateMaterialDraft(ctx context.Context, materialID, authorTeacherID, baseVersionID int64, title string, summary, description *string, content string, changeNote *string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	maxVersion, err := qtx.GetMaxVersionNumberByMaterialID(ctx, materialID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, customerrors.ErrInternal
+	}
+
+	contentHash, err := putMaterialContent(ctx, qtx, content)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := qtx.CreateMaterialVersion(ctx, queries.CreateMaterialVersionParams{
+		MaterialID:      materialID,
+		Title:           title,
+		Summary:         toNullString(summary),
+		Description:     toNullString(description),
+		ContentHash:     contentHash,
+		VersionNumber:   maxVersion + 1,
+		ChangeNote:      toNullString(changeNote),
+		IsDraft:         true,
+		AuthorTeacherID: toNullInt64(&authorTeacherID),
+		BaseVersionID:   toNullInt64(&baseVersionID),
+	})
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	draftID, err := result.LastInsertId()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return draftID, nil
+}
+
+// ListMaterialDraftsByMaterialID returns the material's drafts as the same
+// rows GetMaterialVersionByID returns.
+func (r *MySQLRepository) ListMaterialDraftsByMaterialID(ctx context.Context, materialID int64) ([]queries.GetMaterialVersionByIDRow, error) {
+	rows, err := r.q.ListMaterialDraftsByMaterialID(ctx, materialID)
+	if err != nil {
+		return nil, customerrors.ErrInternal
+	}
+	drafts := make([]queries.GetMaterialVersionByIDRow, len(rows))
+	for i, row := range rows {
+		drafts[i] = queries.GetMaterialVersionByIDRow(row)
+	}
+	return drafts, nil
+}
+
+// UpdateMaterialDraft returns ErrConflict if the draft was published or
+// discarded in the meantime.
+func (r *MySQLRepository) UpdateMaterialDraft(ctx context.Context, draftID int64, title string, summary, description *string, content string, changeNote *string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	contentHash, err := putMaterialContent(ctx, qtx, content)
+	if err != nil {
+		return err
+	}
+
+	result, err := qtx.UpdateMaterialDraft(ctx, queries.UpdateMaterialDraftParams{
+		Title:       title,
+		Summary:     toNullString(summary),
+		Description: toNullString(description),
+		ContentHash: contentHash,
+		ChangeNote:  toNullString(changeNote),
+		ID:          draftID,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if rows == 0 {
+		return customerrors.ErrConflict
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	return nil
+}
+
+// PublishMaterialDraft turns a draft into the material's main version. A
+// draft that is no longer the newest version is renumbered to come after the
+// versions published since it was created. The material's current version
+// must be one of ifMatch (see checkCurrentVersion). It returns ErrConflict if
+// the draft was published or discarded in the meantime.
+func (r *MySQLRepository) PublishMaterialDraft(ctx context.Context, materialID int64, draft queries.GetMaterialVersionByIDRow, ifMatch []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	if err = checkCurrentVersion(ctx, qtx, materialID, ifMatch); err != nil {
+		return err
+	}
+	maxVersion, err := qtx.GetMaxVersionNumberByMaterialID(ctx, materialID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return customerrors.ErrInternal
+	}
+	versionNumber := draft.VersionNumber
+	if versionNumber < maxVersion {
+		versionNumber = maxVersion + 1
+	}
+
+	result, err := qtx.PublishMaterialDraft(ctx, queries.PublishMaterialDraftParams{
+		VersionNumber: versionNumber,
+		ID:            draft.ID,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if rows == 0 {
+		return customerrors.ErrConflict
+	}
+
+	err = qtx.UpdateMaterialVersionMain(ctx, queries.UpdateMaterialVersionMainParams{
+		ID:         draft.ID,
+		MaterialID: materialID,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if err = updateMaterialCurrentVersion(ctx, qtx, materialID, draft.ID); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	return nil
+}
+
+func (r *MySQLRepository) DeleteMaterialDraft(ctx context.Context, draftID int64) error {
+	result, err := r.q.DeleteMaterialDraft(ctx, draftID)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if rows == 0 {
+		return customerrors.ErrNotFound
+	}
+	return nil
+}
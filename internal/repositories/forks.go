@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+)
+
+// ForkMaterial copies version into a new material owned by teacherID that
+// records source as its original.
+func (r *MySQLRepository) ForkMaterial(ctx context.Context, source queries.Material, version queries.GetMaterialVersionByIDRow, teacherID int64, subjectID *int64) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+
+	res, err := qtx.CreateMaterial(ctx, queries.CreateMaterialParams{
+		TeacherID:          teacherID,
+		SubjectID:          toNullInt64(subjectID),
+		OriginalMaterialID: toNullInt64(&source.ID),
+		UpstreamVersionID:  toNullInt64(&version.ID),
+	})
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	materialID, err := res.LastInsertId()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+
+	note := fmt.Sprintf("Forked from material %d v%d", source.ID, version.VersionNumber)
+	_, err = createMainMaterialVersion(
+		ctx,
+		qtx,
+		materialID,
+		teacherID,
+		version.Title,
+		nullStringToPointer(version.Summary),
+		nullStringToPointer(version.Description),
+		version.Content,
+		&note,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return materialID, nil
+}
+
+// updateMaterialUpstreamVersion records the upstream version a fork is synced
+// to. It fails with ErrConflict when the fork's main version is no longer
+// currentVersionID, i.e. the fork changed while it was being synced.
+func updateMaterialUpstreamVersion(ctx context.Context, q *queries.Queries, materialID, currentVersionID, upstreamVersionID int64) error {
+	result, err := q.UpdateMaterialUpstreamVersion(ctx, queries.UpdateMaterialUpstreamVersionParams{
+		UpstreamVersionID: toNullInt64(&upstreamVersionID),
+		ID:                materialID,
+		CurrentVersionID:  toNullInt64(&currentVersionID),
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if rows == 0 {
+		return customerrors.ErrConflict
+	}
+	return nil
+}
+
+func (r *MySQLRepository) UpdateMaterialUpstreamVersion(ctx context.Context, materialID, currentVersionID, upstreamVersionID int64) error {
+	return updateMaterialUpstreamVersion(ctx, r.q, materialID, currentVersionID, upstreamVersionID)
+}
+
+// SyncMaterialFork stores the result of merging upstream changes into a fork
+// as a new main version and moves the fork's upstream pointer along.
+func (r *MySQLRepository) SyncMaterialFork(ctx context.Context, materialID, authorTeacherID, currentVersionID, upstreamVersionID int64, title string, summary, description *string, content string, changeNote string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+
+	if err = updateMaterialUpstreamVersion(ctx, qtx, materialID, currentVersionID, upstreamVersionID); err != nil {
+		return 0, err
+	}
+	versionID, err := createMainMaterialVersion(ctx, qtx, materialID, authorTeacherID, title, summary, description, content, &changeNote)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return versionID, nil
+}
+
+func (r *MySQLRepository) CountMaterialVersionsAfter(ctx context.Context, materialID int64, versionNumber int32) (int64, error) {
+	count, err := r.q.CountMaterialVersionsAfter(ctx, queries.CountMaterialVersionsAfterParams{
+		MaterialID:    materialID,
+		VersionNumber: versionNumber,
+	})
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return count, nil
+}
+
+func (r *MySQLRepository) ListMaterialAncestors(ctx context.Context, materialID int64) ([]queries.ListMaterialAncestorsRow, error) {
+	ancestors, err := r.q.ListMaterialAncestors(ctx, materialID)
+	if err != nil {
+		return nil, customerrors.ErrInternal
+	}
+	return ancestors, nil
+}
+
+func (r *MySQLRepository) ListMaterialDescendants(ctx context.Context, materialID int64) ([]queries.ListMaterialDescendantsRow, error) {
+	descendants, err := r.q.ListMaterialDescendants(ctx, toNullInt64(&materialID))
+	if err != nil {
+		return nil, customerrors.ErrInternal
+	}
+	return descendants, nil
+}
@@ -1,6 +1,27 @@
 package repositories
 
-import "database/sql"
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL error numbers the repositories turn into customerrors
+const (
+	// mysqlDuplicateEntry is a unique or primary key violation
+	mysqlDuplicateEntry = 1062
+	// mysqlDeadlock is the error MySQL rolls a transaction back with when it
+	// picks it as a deadlock victim
+	mysqlDeadlock = 1213
+)
+
+// isMySQLError reports whether err is the MySQL server error number.
+func isMySQLError(err error, number uint16) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == number
+}
 
 func toNullString(s *string) sql.NullString {
 	if s == nil {
@@ -15,3 +36,24 @@ func toNullInt64(i *int64) sql.NullInt64 {
 	}
 	return sql.NullInt64{Int64: *i, Valid: true}
 }
+
+func nullStringToPointer(s sql.NullString) *string {
+	if s.Valid {
+		return &s.String
+	}
+	return nil
+}
+
+func toNullInt32(i *int) sql.NullInt32 {
+	if i == nil {
+		return sql.NullInt32{Valid: false}
+	}
+	return sql.NullInt32{Int32: int32(*i), Valid: true}
+}
+
+func toNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{Valid: false}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+)
+
+func (r *MySQLRepository) ListPendingMaterialProposalsByOwnerTeacherID(ctx context.Context, teacherID int64) ([]queries.GetMaterialProposalByIDRow, error) {
+	rows, err := r.q.ListPendingMaterialProposalsByOwnerTeacherID(ctx, teacherID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrNotFound
+		}
+		return nil, customerrors.ErrInternal
+	}
+	proposals := make([]queries.GetMaterialProposalByIDRow, len(rows))
+	for i, row := range rows {
+		proposals[i] = queries.GetMaterialProposalByIDRow(row)
+	}
+	return proposals, nil
+}
+
+func (r *MySQLRepository) ListMaterialProposalsByAuthorTeacherID(ctx context.Context, teacherID int64) ([]queries.GetMaterialProposalByIDRow, error) {
+	rows, err := r.q.ListMaterialProposalsByAuthorTeacherID(ctx, teacherID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrNotFound
+		}
+		return nil, customerrors.ErrInternal
+	}
+	proposals := make([]queries.GetMaterialProposalByIDRow, len(rows))
+	for i, row := range rows {
+		proposals[i] = queries.GetMaterialProposalByIDRow(row)
+	}
+	return proposals, nil
+}
+
+func (r *MySQLRepository) CountMaterialProposalsByOwnerTeacherID(ctx context.Context, teacherID int64) ([]queries.CountMaterialProposalsByOwnerTeacherIDRow, error) {
+	counts, err := r.q.CountMaterialProposalsByOwnerTeacherID(ctx, teacherID)
+	if err != nil {
+		return nil, customerrors.ErrInternal
+	}
+	return counts, nil
+}
+
+func (r *MySQLRepository) CountMaterialProposalsByAuthorTeacherID(ctx context.Context, teacherID int64) ([]queries.CountMaterialProposalsByAuthorTeacherIDRow, error) {
+	counts, err := r.q.CountMaterialProposalsByAuthorTeacherID(ctx, teacherID)
+	if err != nil {
+		return nil, customerrors.ErrInternal
+	}
+	return counts, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/didrikolofsson/materials/generated/queries"
 	customerrors "github.com/didrikolofsson/materials/internal/errors"
@@ -46,6 +47,17 @@ func (r *MySQLRepository) ListSubjects(ctx context.Context) ([]queries.Subject,
 	return subjects, nil
 }
 
+func (r *MySQLRepository) GetSubjectByID(ctx context.Context, id int64) (queries.Subject, error) {
+	subject, err := r.q.GetSubjectByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return queries.Subject{}, customerrors.ErrNotFound
+		}
+		return queries.Subject{}, customerrors.ErrInternal
+	}
+	return subject, nil
+}
+
 func (r *MySQLRepository) ListMaterials(ctx context.Context) ([]queries.ListMaterialsRow, error) {
 	materials, err := r.q.ListMaterials(ctx)
 	if err != nil {
@@ -79,6 +91,28 @@ func (r *MySQLRepository) GetTeacherByID(ctx context.Context, id int64) (queries
 	return teacher, nil
 }
 
+func (r *MySQLRepository) GetMaterialByID(ctx context.Context, id int64) (queries.Material, error) {
+	material, err := r.q.GetMaterialByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return queries.Material{}, customerrors.ErrNotFound
+		}
+		return queries.Material{}, customerrors.ErrInternal
+	}
+	return material, nil
+}
+
+func (r *MySQLRepository) GetMaterialVersionByID(ctx context.Context, id int64) (queries.GetMaterialVersionByIDRow, error) {
+	version, err := r.q.GetMaterialVersionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return queries.GetMaterialVersionByIDRow{}, customerrors.ErrNotFound
+		}
+		return queries.GetMaterialVersionByIDRow{}, customerrors.ErrInternal
+	}
+	return version, nil
+}
+
 func (r *MySQLRepository) GetTeacherMaterials(ctx context.Context, teacherID int64) ([]queries.GetTeacherMaterialsRow, error) {
 	materials, err := r.q.GetTeacherMaterials(ctx, teacherID)
 	if err != nil {
@@ -104,6 +138,67 @@ func (r *MySQLRepository) GetTeacherMaterialByID(ctx context.Context, teacherID,
 	return material, nil
 }
 
+// ListMaterialsAsOf lists materials as they were at asOf, with the version
+// that was main at the time in place of the current one.
+func (r *MySQLRepository) ListMaterialsAsOf(ctx context.Context, asOf time.Time) ([]queries.ListMaterialsRow, error) {
+	rows, err := r.q.ListMaterialsAsOf(ctx, asOf)
+	if err != nil {
+		return nil, customerrors.ErrInternal
+	}
+	materials := make([]queries.ListMaterialsRow, len(rows))
+	for i, row := range rows {
+		materials[i] = queries.ListMaterialsRow(row)
+	}
+	return materials, nil
+}
+
+func (r *MySQLRepository) GetTeacherMaterialsAsOf(ctx context.Context, teacherID int64, asOf time.Time) ([]queries.GetTeacherMaterialsRow, error) {
+	rows, err := r.q.GetTeacherMaterialsAsOf(ctx, queries.GetTeacherMaterialsAsOfParams{
+		AsOf:      asOf,
+		TeacherID: teacherID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrNotFound
+		}
+		return nil, customerrors.ErrInternal
+	}
+	materials := make([]queries.GetTeacherMaterialsRow, len(rows))
+	for i, row := range rows {
+		materials[i] = queries.GetTeacherMaterialsRow(row)
+	}
+	return materials, nil
+}
+
+func (r *MySQLRepository) GetTeacherMaterialByIDAsOf(ctx context.Context, teacherID, materialID int64, asOf time.Time) (queries.GetTeacherMaterialByIDRow, error) {
+	material, err := r.q.GetTeacherMaterialByIDAsOf(ctx, queries.GetTeacherMaterialByIDAsOfParams{
+		AsOf:      asOf,
+		TeacherID: teacherID,
+		ID:        materialID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return queries.GetTeacherMaterialByIDRow{}, customerrors.ErrNotFound
+		}
+		return queries.GetTeacherMaterialByIDRow{}, customerrors.ErrInternal
+	}
+	return queries.GetTeacherMaterialByIDRow(material), nil
+}
+
+// ListMaterialMainVersionHistory lists the versions a material had as main,
+// oldest first. A version appears once for every time it became main.
+func (r *MySQLRepository) ListMaterialMainVersionHistory(ctx context.Context, materialID int64) ([]queries.ListMaterialVersionsByMaterialIDRow, error) {
+	rows, err := r.q.ListMaterialMainVersionHistory(ctx, materialID)
+	if err != nil {
+		return nil, customerrors.ErrInternal
+	}
+	versions := make([]queries.ListMaterialVersionsByMaterialIDRow, len(rows))
+	for i, row := range rows {
+		versions[i] = queries.ListMaterialVersionsByMaterialIDRow(row)
+	}
+	return versions, nil
+}
+
 func (r *MySQLRepository) CreateInitialTeacherMaterial(
 	ctx context.Context,
 	teacherID int64,
@@ -133,13 +228,19 @@ func (r *MySQLRepository) CreateInitialTeacherMaterial(
 	}
 
 	// Create version
+	contentHash, err := putMaterialContent(ctx, qtx, req.Content)
+	if err != nil {
+		return 0, err
+	}
 	res, err = qtx.CreateMaterialVersion(ctx, queries.CreateMaterialVersionParams{
-		MaterialID:  materialID,
-		Title:       req.Title,
-		Summary:     toNullString(req.Summary),
-		Description: toNullString(req.Description),
-		Content:     req.Content,
-		IsMain:      true,
+		MaterialID:      materialID,
+		Title:           req.Title,
+		Summary:         toNullString(req.Summary),
+		Description:     toNullString(req.Description),
+		ContentHash:     contentHash,
+		IsMain:          true,
+		ChangeNote:      toNullString(req.ChangeNote),
+		AuthorTeacherID: toNullInt64(&teacherID),
 	})
 	if err != nil {
 		return 0, customerrors.ErrInternal
@@ -150,12 +251,8 @@ func (r *MySQLRepository) CreateInitialTeacherMaterial(
 	}
 
 	// Update material current version
-	err = qtx.UpdateMaterialCurrentVersion(ctx, queries.UpdateMaterialCurrentVersionParams{
-		CurrentVersionID: toNullInt64(&versionID),
-		ID:               materialID,
-	})
-	if err != nil {
-		return 0, customerrors.ErrInternal
+	if err = updateMaterialCurrentVersion(ctx, qtx, materialID, versionID); err != nil {
+		return 0, err
 	}
 
 	err = tx.Commit()
@@ -177,72 +274,182 @@ func (r *MySQLRepository) GetMaxVersionNumber(ctx context.Context, materialID in
 	return maxVersion, nil
 }
 
-func (r *MySQLRepository) CreateMaterialVersion(ctx context.Context, materialID int64, title string, summary, description *string, content string, isMain bool) (int64, error) {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return 0, customerrors.ErrInternal
-	}
-	defer tx.Rollback()
-
-	qtx := r.q.WithTx(tx)
+// createMainMaterialVersion appends a version by authorTeacherID with the
+// next version number and makes it the material's main and current version.
+func createMainMaterialVersion(ctx context.Context, qtx *queries.Queries, materialID, authorTeacherID int64, title string, summary, description *string, content string, changeNote *string) (int64, error) {
 	maxVersion, err := qtx.GetMaxVersionNumberByMaterialID(ctx, materialID)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return 0, customerrors.ErrInternal
 	}
-	// If no rows found, maxVersion is 0, so next version will be 1
-	if errors.Is(err, sql.ErrNoRows) {
-		maxVersion = 0
+
+	contentHash, err := putMaterialContent(ctx, qtx, content)
+	if err != nil {
+		return 0, err
 	}
 
 	result, err := qtx.CreateMaterialVersion(ctx, queries.CreateMaterialVersionParams{
-		MaterialID:    materialID,
-		Title:         title,
-		Summary:       toNullString(summary),
-		Description:   toNullString(description),
-		Content:       content,
-		IsMain:        isMain,
-		VersionNumber: maxVersion + 1,
+		MaterialID:      materialID,
+		Title:           title,
+		Summary:         toNullString(summary),
+		Description:     toNullString(description),
+		ContentHash:     contentHash,
+		IsMain:          true,
+		VersionNumber:   maxVersion + 1,
+		ChangeNote:      toNullString(changeNote),
+		AuthorTeacherID: toNullInt64(&authorTeacherID),
 	})
 	if err != nil {
 		return 0, customerrors.ErrInternal
 	}
-
-	err = tx.Commit()
+	versionID, err := result.LastInsertId()
 	if err != nil {
 		return 0, customerrors.ErrInternal
 	}
 
-	versionID, err := result.LastInsertId()
+	err = qtx.UpdateMaterialVersionMain(ctx, queries.UpdateMaterialVersionMainParams{
+		ID:         versionID,
+		MaterialID: materialID,
+	})
 	if err != nil {
 		return 0, customerrors.ErrInternal
 	}
+
+	if err = updateMaterialCurrentVersion(ctx, qtx, materialID, versionID); err != nil {
+		return 0, err
+	}
+
 	return versionID, nil
 }
 
-func (r *MySQLRepository) UpdateMaterialCurrentVersion(ctx context.Context, materialID, versionID int64) error {
-	err := r.q.UpdateMaterialCurrentVersion(ctx, queries.UpdateMaterialCurrentVersionParams{
+// updateMaterialCurrentVersion points the material at versionID and records
+// the change in its main version history.
+func updateMaterialCurrentVersion(ctx context.Context, qtx *queries.Queries, materialID, versionID int64) error {
+	err := qtx.UpdateMaterialCurrentVersion(ctx, queries.UpdateMaterialCurrentVersionParams{
 		CurrentVersionID: toNullInt64(&versionID),
 		ID:               materialID,
 	})
 	if err != nil {
 		return customerrors.ErrInternal
 	}
+	err = qtx.CreateMaterialMainVersion(ctx, queries.CreateMaterialMainVersionParams{
+		MaterialID:        materialID,
+		MaterialVersionID: toNullInt64(&versionID),
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
 	return nil
 }
 
-func (r *MySQLRepository) UpdateMaterialVersionMain(ctx context.Context, materialID, versionID int64) error {
-	err := r.q.UpdateMaterialVersionMain(ctx, queries.UpdateMaterialVersionMainParams{
+// checkCurrentVersion locks the material row and returns
+// ErrPreconditionFailed unless its current version is one of ifMatch. A nil
+// ifMatch skips the check, while an empty one never matches.
+func checkCurrentVersion(ctx context.Context, qtx *queries.Queries, materialID int64, ifMatch []int64) error {
+	current, err := qtx.GetMaterialCurrentVersionForUpdate(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customerrors.ErrNotFound
+		}
+		return customerrors.ErrInternal
+	}
+	if ifMatch == nil {
+		return nil
+	}
+	for _, versionID := range ifMatch {
+		if current.Valid && current.Int64 == versionID {
+			return nil
+		}
+	}
+	return customerrors.ErrPreconditionFailed
+}
+
+// CreateMainMaterialVersion appends a new main version, provided the
+// material's current version is one of ifMatch (see checkCurrentVersion).
+func (r *MySQLRepository) CreateMainMaterialVersion(ctx context.Context, materialID, authorTeacherID int64, ifMatch []int64, title string, summary, description *string, content string, changeNote *string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	if err = checkCurrentVersion(ctx, qtx, materialID, ifMatch); err != nil {
+		return 0, err
+	}
+	versionID, err := createMainMaterialVersion(ctx, qtx, materialID, authorTeacherID, title, summary, description, content, changeNote)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return versionID, nil
+}
+
+// SetMaterialMainVersion makes an existing version the material's main and
+// current version, provided the current version is one of ifMatch (see
+// checkCurrentVersion).
+func (r *MySQLRepository) SetMaterialMainVersion(ctx context.Context, materialID, versionID int64, ifMatch []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	if err = checkCurrentVersion(ctx, qtx, materialID, ifMatch); err != nil {
+		return err
+	}
+
+	err = qtx.UpdateMaterialVersionMain(ctx, queries.UpdateMaterialVersionMainParams{
 		ID:         versionID,
 		MaterialID: materialID,
 	})
 	if err != nil {
 		return customerrors.ErrInternal
 	}
+	if err = updateMaterialCurrentVersion(ctx, qtx, materialID, versionID); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
 	return nil
 }
 
+// DeleteMaterial deletes the material with its proposals and versions. The
+// proposals and the main version history go first, since they keep the
+// versions from being deleted.
 func (r *MySQLRepository) DeleteMaterial(ctx context.Context, id int64) error {
-	err := r.q.DeleteMaterial(ctx, id)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	err = qtx.ClearMaterialProposalsSupersededBy(ctx, id)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	err = qtx.DeleteMaterialProposals(ctx, id)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	err = qtx.DeleteMaterialMainVersions(ctx, id)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	err = qtx.DeleteMaterial(ctx, id)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return customerrors.ErrInternal
 	}
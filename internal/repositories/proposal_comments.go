@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+func (r *MySQLRepository) CreateMaterialProposalComment(ctx context.Context, proposalID int64, req models.CreateMaterialProposalCommentRequest) (int64, error) {
+	res, err := r.q.CreateMaterialProposalComment(ctx, queries.CreateMaterialProposalCommentParams{
+		ProposalID:      proposalID,
+		AuthorTeacherID: req.AuthorTeacherID,
+		ParentCommentID: toNullInt64(req.ParentCommentID),
+		LineStart:       toNullInt32(req.LineStart),
+		LineEnd:         toNullInt32(req.LineEnd),
+		Body:            req.Body,
+	})
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	commentID, err := res.LastInsertId()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return commentID, nil
+}
+
+func (r *MySQLRepository) GetMaterialProposalCommentByID(ctx context.Context, id int64) (queries.MaterialProposalComment, error) {
+	comment, err := r.q.GetMaterialProposalCommentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return queries.MaterialProposalComment{}, customerrors.ErrNotFound
+		}
+		return queries.MaterialProposalComment{}, customerrors.ErrInternal
+	}
+	return comment, nil
+}
+
+func (r *MySQLRepository) ListMaterialProposalCommentsByProposalID(ctx context.Context, proposalID int64) ([]queries.MaterialProposalComment, error) {
+	comments, err := r.q.ListMaterialProposalCommentsByProposalID(ctx, proposalID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrNotFound
+		}
+		return nil, customerrors.ErrInternal
+	}
+	return comments, nil
+}
+
+func (r *MySQLRepository) ResolveMaterialProposalComment(ctx context.Context, commentID, teacherID int64) error {
+	res, err := r.q.ResolveMaterialProposalComment(ctx, queries.ResolveMaterialProposalCommentParams{
+		ResolvedByTeacherID: toNullInt64(&teacherID),
+		ID:                  commentID,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if affected == 0 {
+		return customerrors.ErrConflict
+	}
+	return nil
+}
+
+func (r *MySQLRepository) ReopenMaterialProposalComment(ctx context.Context, commentID int64) error {
+	res, err := r.q.ReopenMaterialProposalComment(ctx, commentID)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if affected == 0 {
+		return customerrors.ErrConflict
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// CreateMaterialProposal stores a proposal with its first revision and
+// supersedes any proposal the author still has pending on the material.
+func (r *MySQLRepository) CreateMaterialProposal(
+	ctx context.Context,
+	materialID int64,
+	ownerTeacherID int64,
+	req models.CreateMaterialProposalRequest,
+) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+
+	contentHash, err := putMaterialContent(ctx, qtx, req.Content)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := qtx.CreateMaterialProposal(ctx, queries.CreateMaterialProposalParams{
+		MaterialID:        materialID,
+		MaterialVersionID: req.MaterialVersionID,
+		OwnerTeacherID:    ownerTeacherID,
+		AuthorTeacherID:   req.AuthorTeacherID,
+		Title:             req.Title,
+		Summary:           toNullString(req.Summary),
+		Description:       toNullString(req.Description),
+		ContentHash:       contentHash,
+	})
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	proposalID, err := res.LastInsertId()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+
+	err = createMaterialProposalRevision(
+		ctx,
+		qtx,
+		proposalID,
+		req.MaterialVersionID,
+		req.Title,
+		req.Summary,
+		req.Description,
+		req.Content,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = qtx.SupersedeMaterialProposals(ctx, queries.SupersedeMaterialProposalsParams{
+		SupersededByProposalID: toNullInt64(&proposalID),
+		MaterialID:             materialID,
+		AuthorTeacherID:        req.AuthorTeacherID,
+		ID:                     proposalID,
+	})
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return proposalID, nil
+}
+
+func (r *MySQLRepository) GetMaterialProposalByID(ctx context.Context, id int64) (queries.GetMaterialProposalByIDRow, error) {
+	proposal, err := r.q.GetMaterialProposalByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return queries.GetMaterialProposalByIDRow{}, customerrors.ErrNotFound
+		}
+		return queries.GetMaterialProposalByIDRow{}, customerrors.ErrInternal
+	}
+	return proposal, nil
+}
+
+func (r *MySQLRepository) ListMaterialProposalsByMaterialID(ctx context.Context, materialID int64) ([]queries.GetMaterialProposalByIDRow, error) {
+	rows, err := r.q.ListMaterialProposalsByMaterialID(ctx, materialID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrNotFound
+		}
+		return nil, customerrors.ErrInternal
+	}
+	proposals := make([]queries.GetMaterialProposalByIDRow, len(rows))
+	for i, row := range rows {
+		proposals[i] = queries.GetMaterialProposalByIDRow(row)
+	}
+	return proposals, nil
+}
+
+// ApproveMaterialProposal records a teacher's approval of a pending proposal.
+// Once the proposal has the required number of approvals it is marked as
+// approved and merged into a new main version of its material, all in a
+// single transaction. The new version's ID is returned, or 0 while the
+// proposal still awaits approvals.
+//
+// The approval only applies to the proposal as the caller read it. The row is
+// locked and read again, and ErrConflict is returned when it was revised,
+// rebased, decided or went stale in the meantime, or when the teacher has
+// already approved it.
+func (r *MySQLRepository) ApproveMaterialProposal(ctx context.Context, read queries.GetMaterialProposalByIDRow, teacherID int64, requiredApprovals int) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+
+	locked, err := qtx.GetMaterialProposalForUpdate(ctx, read.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, customerrors.ErrNotFound
+		}
+		return 0, customerrors.ErrInternal
+	}
+	proposal := queries.GetMaterialProposalByIDRow(locked)
+	if proposal.Status != queries.MaterialProposalsStatusPENDING ||
+		!sameMaterialProposalContent(proposal, read) ||
+		!proposal.CurrentVersionID.Valid ||
+		proposal.CurrentVersionID.Int64 != proposal.MaterialVersionID {
+		return 0, customerrors.ErrConflict
+	}
+
+	err = qtx.CreateMaterialProposalApproval(ctx, queries.CreateMaterialProposalApprovalParams{
+		ProposalID: proposal.ID,
+		TeacherID:  teacherID,
+	})
+	if err != nil {
+		if isMySQLError(err, mysqlDuplicateEntry) {
+			return 0, customerrors.ErrConflict
+		}
+		return 0, customerrors.ErrInternal
+	}
+	approvals, err := qtx.CountMaterialProposalApprovals(ctx, proposal.ID)
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+
+	var versionID int64
+	if approvals >= int64(requiredApprovals) {
+		if err = decideMaterialProposal(ctx, qtx, proposal.ID, teacherID, queries.MaterialProposalsStatusAPPROVED); err != nil {
+			return 0, err
+		}
+
+		// The merged version is the proposal author's work
+		note := fmt.Sprintf("Merged proposal %d", proposal.ID)
+		versionID, err = createMainMaterialVersion(
+			ctx,
+			qtx,
+			proposal.MaterialID,
+			proposal.AuthorTeacherID,
+			proposal.Title,
+			nullStringToPointer(proposal.Summary),
+			nullStringToPointer(proposal.Description),
+			proposal.Content,
+			&note,
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return versionID, nil
+}
+
+// sameMaterialProposalContent reports whether a and b propose the same change
+// on the same base version.
+func sameMaterialProposalContent(a, b queries.GetMaterialProposalByIDRow) bool {
+	return a.MaterialVersionID == b.MaterialVersionID &&
+		a.Title == b.Title &&
+		a.Summary == b.Summary &&
+		a.Description == b.Description &&
+		a.Content == b.Content
+}
+
+func (r *MySQLRepository) RejectMaterialProposal(ctx context.Context, proposalID, deciderTeacherID int64) error {
+	return decideMaterialProposal(ctx, r.q, proposalID, deciderTeacherID, queries.MaterialProposalsStatusREJECTED)
+}
+
+// decideMaterialProposal moves a proposal out of PENDING. It fails with
+// ErrConflict if the proposal was already decided.
+func decideMaterialProposal(ctx context.Context, q *queries.Queries, proposalID, deciderTeacherID int64, status queries.MaterialProposalsStatus) error {
+	res, err := q.DecideMaterialProposal(ctx, queries.DecideMaterialProposalParams{
+		Status:             status,
+		DecidedByTeacherID: toNullInt64(&deciderTeacherID),
+		ID:                 proposalID,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if affected == 0 {
+		return customerrors.ErrConflict
+	}
+	return nil
+}
+
+// ReviseMaterialProposal replaces what a pending proposal proposes and
+// records the change as a new revision. Approvals given so far were for the
+// previous content, so they are cleared.
+func (r *MySQLRepository) ReviseMaterialProposal(ctx context.Context, proposalID, versionID int64, title string, summary, description *string, content string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+
+	contentHash, err := putMaterialContent(ctx, qtx, content)
+	if err != nil {
+		return err
+	}
+
+	res, err := qtx.UpdateMaterialProposalContent(ctx, queries.UpdateMaterialProposalContentParams{
+		MaterialVersionID: versionID,
+		Title:             title,
+		Summary:           toNullString(summary),
+		Description:       toNullString(description),
+		ContentHash:       contentHash,
+		ID:                proposalID,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if affected == 0 {
+		return customerrors.ErrConflict
+	}
+
+	err = createMaterialProposalRevision(ctx, qtx, proposalID, versionID, title, summary, description, content)
+	if err != nil {
+		return err
+	}
+
+	if err = qtx.DeleteMaterialProposalApprovals(ctx, proposalID); err != nil {
+		return customerrors.ErrInternal
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	return nil
+}
+
+func (r *MySQLRepository) WithdrawMaterialProposal(ctx context.Context, proposalID, authorTeacherID int64) error {
+	return decideMaterialProposal(ctx, r.q, proposalID, authorTeacherID, queries.MaterialProposalsStatusWITHDRAWN)
+}
+
+func (r *MySQLRepository) ListMaterialProposalRevisionsByProposalID(ctx context.Context, proposalID int64) ([]queries.ListMaterialProposalRevisionsByProposalIDRow, error) {
+	revisions, err := r.q.ListMaterialProposalRevisionsByProposalID(ctx, proposalID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrNotFound
+		}
+		return nil, customerrors.ErrInternal
+	}
+	return revisions, nil
+}
+
+func createMaterialProposalRevision(ctx context.Context, qtx *queries.Queries, proposalID, versionID int64, title string, summary, description *string, content string) error {
+	maxRevision, err := qtx.GetMaxMaterialProposalRevisionNumber(ctx, proposalID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return customerrors.ErrInternal
+	}
+
+	contentHash, err := putMaterialContent(ctx, qtx, content)
+	if err != nil {
+		return err
+	}
+
+	err = qtx.CreateMaterialProposalRevision(ctx, queries.CreateMaterialProposalRevisionParams{
+		ProposalID:        proposalID,
+		RevisionNumber:    maxRevision + 1,
+		MaterialVersionID: versionID,
+		Title:             title,
+		Summary:           toNullString(summary),
+		Description:       toNullString(description),
+		ContentHash:       contentHash,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	return nil
+}
+
+func (r *MySQLRepository) ListIdleMaterialProposals(ctx context.Context, idleSince time.Time) ([]queries.GetMaterialProposalByIDRow, error) {
+	rows, err := r.q.ListIdleMaterialProposals(ctx, idleSince)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrNotFound
+		}
+		return nil, customerrors.ErrInternal
+	}
+	proposals := make([]queries.GetMaterialProposalByIDRow, len(rows))
+	for i, row := range rows {
+		proposals[i] = queries.GetMaterialProposalByIDRow(row)
+	}
+	return proposals, nil
+}
+
+func (r *MySQLRepository) MarkMaterialProposalReminded(ctx context.Context, proposalID int64) error {
+	err := r.q.MarkMaterialProposalReminded(ctx, proposalID)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	return nil
+}
+
+func (r *MySQLRepository) ExpireMaterialProposals(ctx context.Context, createdBefore time.Time) (int64, error) {
+	res, err := r.q.ExpireMaterialProposals(ctx, createdBefore)
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	expired, err := res.RowsAffected()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return expired, nil
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+)
+
+func (r *MySQLRepository) ReportMaterialProposalsByTeacher(ctx context.Context, from, to *time.Time) ([]queries.ReportMaterialProposalsByTeacherRow, []queries.ReportMaterialProposalMedianDecisionTimeByTeacherRow, error) {
+	counts, err := r.q.ReportMaterialProposalsByTeacher(ctx, queries.ReportMaterialProposalsByTeacherParams{
+		CreatedFrom: toNullTime(from),
+		CreatedTo:   toNullTime(to),
+	})
+	if err != nil {
+		return nil, nil, customerrors.ErrInternal
+	}
+	medians, err := r.q.ReportMaterialProposalMedianDecisionTimeByTeacher(ctx, queries.ReportMaterialProposalMedianDecisionTimeByTeacherParams{
+		CreatedFrom: toNullTime(from),
+		CreatedTo:   toNullTime(to),
+	})
+	if err != nil {
+		return nil, nil, customerrors.ErrInternal
+	}
+	return counts, medians, nil
+}
+
+func (r *MySQLRepository) ReportMaterialProposalsBySubject(ctx context.Context, from, to *time.Time) ([]queries.ReportMaterialProposalsBySubjectRow, []queries.ReportMaterialProposalMedianDecisionTimeBySubjectRow, error) {
+	counts, err := r.q.ReportMaterialProposalsBySubject(ctx, queries.ReportMaterialProposalsBySubjectParams{
+		CreatedFrom: toNullTime(from),
+		CreatedTo:   toNullTime(to),
+	})
+	if err != nil {
+		return nil, nil, customerrors.ErrInternal
+	}
+	medians, err := r.q.ReportMaterialProposalMedianDecisionTimeBySubject(ctx, queries.ReportMaterialProposalMedianDecisionTimeBySubjectParams{
+		CreatedFrom: toNullTime(from),
+		CreatedTo:   toNullTime(to),
+	})
+	if err != nil {
+		return nil, nil, customerrors.ErrInternal
+	}
+	return counts, medians, nil
+}
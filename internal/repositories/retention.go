@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+)
+
+func (r *MySQLRepository) ListMaterialVersionsForRetention(ctx context.Context) ([]queries.ListMaterialVersionsForRetentionRow, error) {
+	versions, err := r.q.ListMaterialVersionsForRetention(ctx)
+	if err != nil {
+		return nil, customerrors.ErrInternal
+	}
+	return versions, nil
+}
+
+// DeleteRetiredMaterialVersions deletes the given versions, and the main
+// version history entries that were not the last of their day, in one
+// transaction and returns how many were deleted. Versions that became main,
+// drafts or referenced since they were selected are skipped, unless history
+// was already deleted for them, in which case nothing is deleted and
+// ErrConflict is returned.
+func (r *MySQLRepository) DeleteRetiredMaterialVersions(ctx context.Context, versionIDs []int64) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	var deleted int64
+	for _, versionID := range versionIDs {
+		history, err := qtx.DeleteRetiredMaterialMainVersions(ctx, versionID)
+		if err != nil {
+			return 0, customerrors.ErrInternal
+		}
+		result, err := qtx.DeleteRetiredMaterialVersion(ctx, versionID)
+		if err != nil {
+			return 0, customerrors.ErrInternal
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return 0, customerrors.ErrInternal
+		}
+		if rows == 0 && history > 0 {
+			return 0, customerrors.ErrConflict
+		}
+		deleted += rows
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return deleted, nil
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+)
+
+func (r *MySQLRepository) GetMaterialReviewPolicy(ctx context.Context, materialID int64) (queries.ReviewPolicy, []int64, error) {
+	policy, err := r.q.GetReviewPolicyByMaterialID(ctx, toNullInt64(&materialID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return queries.ReviewPolicy{}, nil, customerrors.ErrNotFound
+		}
+		return queries.ReviewPolicy{}, nil, customerrors.ErrInternal
+	}
+	reviewers, err := r.q.ListReviewPolicyReviewers(ctx, policy.ID)
+	if err != nil {
+		return queries.ReviewPolicy{}, nil, customerrors.ErrInternal
+	}
+	return policy, reviewers, nil
+}
+
+func (r *MySQLRepository) GetSubjectReviewPolicy(ctx context.Context, subjectID int64) (queries.ReviewPolicy, []int64, error) {
+	policy, err := r.q.GetReviewPolicyBySubjectID(ctx, toNullInt64(&subjectID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return queries.ReviewPolicy{}, nil, customerrors.ErrNotFound
+		}
+		return queries.ReviewPolicy{}, nil, customerrors.ErrInternal
+	}
+	reviewers, err := r.q.ListReviewPolicyReviewers(ctx, policy.ID)
+	if err != nil {
+		return queries.ReviewPolicy{}, nil, customerrors.ErrInternal
+	}
+	return policy, reviewers, nil
+}
+
+func (r *MySQLRepository) SetMaterialReviewPolicy(ctx context.Context, materialID int64, requiredApprovals int32, ownerCanSelfApprove bool, reviewerIDs []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+
+	err = qtx.UpsertMaterialReviewPolicy(ctx, queries.UpsertMaterialReviewPolicyParams{
+		MaterialID:          toNullInt64(&materialID),
+		RequiredApprovals:   requiredApprovals,
+		OwnerCanSelfApprove: ownerCanSelfApprove,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	policy, err := qtx.GetReviewPolicyByMaterialID(ctx, toNullInt64(&materialID))
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if err = setReviewPolicyReviewers(ctx, qtx, policy.ID, reviewerIDs); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	return nil
+}
+
+func (r *MySQLRepository) SetSubjectReviewPolicy(ctx context.Context, subjectID int64, requiredApprovals int32, ownerCanSelfApprove bool, reviewerIDs []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+
+	err = qtx.UpsertSubjectReviewPolicy(ctx, queries.UpsertSubjectReviewPolicyParams{
+		SubjectID:           toNullInt64(&subjectID),
+		RequiredApprovals:   requiredApprovals,
+		OwnerCanSelfApprove: ownerCanSelfApprove,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	policy, err := qtx.GetReviewPolicyBySubjectID(ctx, toNullInt64(&subjectID))
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if err = setReviewPolicyReviewers(ctx, qtx, policy.ID, reviewerIDs); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	return nil
+}
+
+// setReviewPolicyReviewers replaces the reviewers of a policy.
+func setReviewPolicyReviewers(ctx context.Context, qtx *queries.Queries, policyID int64, reviewerIDs []int64) error {
+	if err := qtx.DeleteReviewPolicyReviewers(ctx, policyID); err != nil {
+		return customerrors.ErrInternal
+	}
+	for _, reviewerID := range reviewerIDs {
+		err := qtx.CreateReviewPolicyReviewer(ctx, queries.CreateReviewPolicyReviewerParams{
+			PolicyID:  policyID,
+			TeacherID: reviewerID,
+		})
+		if err != nil {
+			return customerrors.ErrInternal
+		}
+	}
+	return nil
+}
+
+func (r *MySQLRepository) ListMaterialProposalApprovals(ctx context.Context, proposalID int64) ([]queries.MaterialProposalApproval, error) {
+	approvals, err := r.q.ListMaterialProposalApprovals(ctx, proposalID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrNotFound
+		}
+		return nil, customerrors.ErrInternal
+	}
+	return approvals, nil
+}
@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+)
+
+func (r *MySQLRepository) CreateSubject(ctx context.Context, name string, parentSubjectID *int64) (int64, error) {
+	res, err := r.q.CreateSubject(ctx, queries.CreateSubjectParams{
+		Name:            name,
+		ParentSubjectID: toNullInt64(parentSubjectID),
+	})
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	subjectID, err := res.LastInsertId()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return subjectID, nil
+}
+
+// lockSubjects locks the subject rows for the rest of the transaction. They
+// are locked in ascending ID order so two transactions locking the same pair
+// wait for each other instead of deadlocking; a deadlock with anything else
+// returns ErrConflict.
+func lockSubjects(ctx context.Context, qtx *queries.Queries, subjectIDs ...int64) error {
+	for _, subjectID := range slices.Sorted(slices.Values(subjectIDs)) {
+		if _, err := qtx.GetSubjectForUpdate(ctx, subjectID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return customerrors.ErrNotFound
+			}
+			if isMySQLError(err, mysqlDeadlock) {
+				return customerrors.ErrConflict
+			}
+			return customerrors.ErrInternal
+		}
+	}
+	return nil
+}
+
+// UpdateSubject renames the subject and moves it under parentSubjectID, or
+// to the top level when that is nil. Moving a subject under itself or one of
+// its descendants would make a cycle and returns ErrBadRequest. Losing a
+// deadlock to a concurrent change returns ErrConflict.
+func (r *MySQLRepository) UpdateSubject(ctx context.Context, subjectID int64, name string, parentSubjectID *int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+
+	// Both ends stay locked so concurrent moves cannot close a cycle between
+	// them after the check below
+	lockIDs := []int64{subjectID}
+	if parentSubjectID != nil {
+		if *parentSubjectID == subjectID {
+			return customerrors.ErrBadRequest
+		}
+		lockIDs = append(lockIDs, *parentSubjectID)
+	}
+	if err = lockSubjects(ctx, qtx, lockIDs...); err != nil {
+		return err
+	}
+	if parentSubjectID != nil {
+		descendants, err := qtx.ListSubjectDescendantIDs(ctx, toNullInt64(&subjectID))
+		if err != nil {
+			return customerrors.ErrInternal
+		}
+		if slices.Contains(descendants, *parentSubjectID) {
+			return customerrors.ErrBadRequest
+		}
+	}
+
+	err = qtx.UpdateSubject(ctx, queries.UpdateSubjectParams{
+		Name:            name,
+		ParentSubjectID: toNullInt64(parentSubjectID),
+		ID:              subjectID,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerrors.ErrInternal
+	}
+	return nil
+}
+
+// DeleteSubject deletes a subject without child subjects. Its materials are
+// moved to reassignToSubjectID first; when that is nil a subject that still
+// has materials is not deleted. Both cases return ErrConflict, as does losing
+// a deadlock to a concurrent change.
+func (r *MySQLRepository) DeleteSubject(ctx context.Context, subjectID int64, reassignToSubjectID *int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+
+	lockIDs := []int64{subjectID}
+	if reassignToSubjectID != nil {
+		lockIDs = append(lockIDs, *reassignToSubjectID)
+	}
+	if err = lockSubjects(ctx, qtx, lockIDs...); err != nil {
+		return err
+	}
+	children, err := qtx.CountSubjectChildren(ctx, toNullInt64(&subjectID))
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if children > 0 {
+		return customerrors.ErrConflict
+	}
+
+	if reassignToSubjectID != nil {
+		err = qtx.ReassignSubjectMaterials(ctx, queries.ReassignSubjectMaterialsParams{
+			ToSubjectID:   toNullInt64(reassignToSubjectID),
+			FromSubjectID: toNullInt64(&subjectID),
+		})
+		if err != nil {
+			return customerrors.ErrInternal
+		}
+	} else {
+		materials, err := qtx.CountSubjectMaterials(ctx, toNullInt64(&subjectID))
+		if err != nil {
+			return customerrors.ErrInternal
+		}
+		if materials > 0 {
+			return customerrors.ErrConflict
+		}
+	}
+
+	if err = qtx.DeleteSubject(ctx, subjectID); err != nil {
+		return customerrors.ErrInternal
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerrors.ErrInternal
+	}
+	return nil
+}
+
+func (r *MySQLRepository) ListSubjectMaterials(ctx context.Context, subjectID int64) ([]queries.ListSubjectMaterialsRow, error) {
+	materials, err := r.q.ListSubjectMaterials(ctx, subjectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrNotFound
+		}
+		return nil, customerrors.ErrInternal
+	}
+	return materials, nil
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+)
+
+// CreateMaterialVersionTag returns ErrConflict when the material already has
+// a tag with the name.
+func (r *MySQLRepository) CreateMaterialVersionTag(ctx context.Context, materialID, versionID int64, name string) error {
+	err := r.q.CreateMaterialVersionTag(ctx, queries.CreateMaterialVersionTagParams{
+		MaterialID:        materialID,
+		MaterialVersionID: versionID,
+		Name:              name,
+	})
+	if err != nil {
+		if isMySQLError(err, mysqlDuplicateEntry) {
+			return customerrors.ErrConflict
+		}
+		return customerrors.ErrInternal
+	}
+	return nil
+}
+
+func (r *MySQLRepository) GetMaterialVersionTagByName(ctx context.Context, materialID int64, name string) (queries.MaterialVersionTag, error) {
+	tag, err := r.q.GetMaterialVersionTagByName(ctx, queries.GetMaterialVersionTagByNameParams{
+		MaterialID: materialID,
+		Name:       name,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return queries.MaterialVersionTag{}, customerrors.ErrNotFound
+		}
+		return queries.MaterialVersionTag{}, customerrors.ErrInternal
+	}
+	return tag, nil
+}
+
+func (r *MySQLRepository) ListMaterialVersionTagsByVersionID(ctx context.Context, versionID int64) ([]queries.MaterialVersionTag, error) {
+	tags, err := r.q.ListMaterialVersionTagsByVersionID(ctx, versionID)
+	if err != nil {
+		return nil, customerrors.ErrInternal
+	}
+	return tags, nil
+}
+
+func (r *MySQLRepository) DeleteMaterialVersionTag(ctx context.Context, versionID int64, name string) error {
+	result, err := r.q.DeleteMaterialVersionTag(ctx, queries.DeleteMaterialVersionTagParams{
+		MaterialVersionID: versionID,
+		Name:              name,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if rows == 0 {
+		return customerrors.ErrNotFound
+	}
+	return nil
+}
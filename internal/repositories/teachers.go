@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+)
+
+func (r *MySQLRepository) CreateTeacher(ctx context.Context, name string) (int64, error) {
+	res, err := r.q.CreateTeacher(ctx, name)
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	teacherID, err := res.LastInsertId()
+	if err != nil {
+		return 0, customerrors.ErrInternal
+	}
+	return teacherID, nil
+}
+
+func (r *MySQLRepository) UpdateTeacherName(ctx context.Context, teacherID int64, name string) error {
+	err := r.q.UpdateTeacherName(ctx, queries.UpdateTeacherNameParams{
+		Name: name,
+		ID:   teacherID,
+	})
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	return nil
+}
+
+// DeactivateTeacher returns ErrConflict when the teacher is already
+// deactivated.
+func (r *MySQLRepository) DeactivateTeacher(ctx context.Context, teacherID int64) error {
+	result, err := r.q.DeactivateTeacher(ctx, teacherID)
+	return teacherActivationResult(result, err)
+}
+
+// ReactivateTeacher returns ErrConflict when the teacher is already active.
+func (r *MySQLRepository) ReactivateTeacher(ctx context.Context, teacherID int64) error {
+	result, err := r.q.ReactivateTeacher(ctx, teacherID)
+	return teacherActivationResult(result, err)
+}
+
+func teacherActivationResult(result sql.Result, err error) error {
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return customerrors.ErrInternal
+	}
+	if rows == 0 {
+		return customerrors.ErrConflict
+	}
+	return nil
+}
@@ -3,7 +3,9 @@ package seed
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"math/rand/v2"
@@ -138,16 +140,31 @@ func seedMaterialVersions(ctx context.Context, qtx *queries.Queries) error {
 	}
 
 	for _, material := range materials {
+		// Seeded versions are written by the material's owner
+		owner, err := qtx.GetMaterialByID(ctx, material.ID)
+		if err != nil {
+			return fmt.Errorf("failed to query material: %w", err)
+		}
 		for range nVersions {
 			versionNumber++
+			content := faker.Paragraph()
+			sum := sha256.Sum256([]byte(content))
+			contentHash := hex.EncodeToString(sum[:])
+			if err := qtx.CreateMaterialContent(ctx, queries.CreateMaterialContentParams{
+				Hash: contentHash,
+				Body: content,
+			}); err != nil {
+				return fmt.Errorf("failed to insert material content: %w", err)
+			}
 			result, err := qtx.CreateMaterialVersion(ctx, queries.CreateMaterialVersionParams{
-				MaterialID:    material.ID,
-				Title:         faker.Word(),
-				Summary:       sql.NullString{String: faker.Sentence(), Valid: true},
-				Description:   sql.NullString{String: faker.Sentence(), Valid: true},
-				Content:       faker.Paragraph(),
-				VersionNumber: int32(versionNumber),
-				IsMain:        versionNumber == 1,
+				MaterialID:      material.ID,
+				Title:           faker.Word(),
+				Summary:         sql.NullString{String: faker.Sentence(), Valid: true},
+				Description:     sql.NullString{String: faker.Sentence(), Valid: true},
+				ContentHash:     contentHash,
+				VersionNumber:   int32(versionNumber),
+				IsMain:          versionNumber == 1,
+				AuthorTeacherID: sql.NullInt64{Int64: owner.TeacherID, Valid: true},
 			})
 			if err != nil {
 				return fmt.Errorf("failed to insert material version: %w", err)
@@ -163,6 +180,12 @@ func seedMaterialVersions(ctx context.Context, qtx *queries.Queries) error {
 				}); err != nil {
 					return fmt.Errorf("failed to update material current version: %w", err)
 				}
+				if err := qtx.CreateMaterialMainVersion(ctx, queries.CreateMaterialMainVersionParams{
+					MaterialID:        material.ID,
+					MaterialVersionID: sql.NullInt64{Int64: versionID, Valid: true},
+				}); err != nil {
+					return fmt.Errorf("failed to record material main version: %w", err)
+				}
 			}
 		}
 		versionNumber = 0
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// toMaterialAsOf converts a material read as of a past moment, where
+// CurrentVersionID is the version that was main at the time.
+func toMaterialAsOf(material queries.ListMaterialsRow) models.Material {
+	return models.Material{
+		ID:               material.ID,
+		TeacherName:      material.TeacherName,
+		SubjectName:      material.SubjectName,
+		CreatedAt:        material.CreatedAt,
+		Title:            material.Title,
+		Description:      nullStringToPointer(material.Description),
+		Summary:          nullStringToPointer(material.Summary),
+		CurrentVersionID: material.CurrentVersionID.Int64,
+	}
+}
+
+// ListMaterialsAsOf lists the materials as they were at asOf, according to
+// their main version history. Materials created after asOf are left out.
+// Version retention keeps the version each day ended with, so in a day it
+// has since thinned a material reads as its last version of the previous day
+// until that day's last change.
+func (s *Services) ListMaterialsAsOf(ctx context.Context, asOf time.Time) ([]models.Material, error) {
+	res, err := s.repos.ListMaterialsAsOf(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+	materials := make([]models.Material, len(res))
+	for i, material := range res {
+		materials[i] = toMaterialAsOf(material)
+	}
+	return materials, nil
+}
+
+// GetTeacherMaterialsAsOf is GetTeacherMaterials as of a past moment, see
+// ListMaterialsAsOf.
+func (s *Services) GetTeacherMaterialsAsOf(ctx context.Context, teacherID int64, asOf time.Time) ([]models.Material, error) {
+	res, err := s.repos.GetTeacherMaterialsAsOf(ctx, teacherID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	materials := make([]models.Material, len(res))
+	for i, material := range res {
+		materials[i] = toMaterialAsOf(queries.ListMaterialsRow(material))
+	}
+	return materials, nil
+}
+
+// GetTeacherMaterialByIDAsOf is GetTeacherMaterialByID as of a past moment,
+// see ListMaterialsAsOf.
+func (s *Services) GetTeacherMaterialByIDAsOf(ctx context.Context, teacherID, materialID int64, asOf time.Time) (models.Material, error) {
+	material, err := s.repos.GetTeacherMaterialByIDAsOf(ctx, teacherID, materialID, asOf)
+	if err != nil {
+		return models.Material{}, err
+	}
+	return toMaterialAsOf(queries.ListMaterialsRow(material)), nil
+}
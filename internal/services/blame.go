@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	"github.com/didrikolofsson/materials/internal/diff"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// contentLines splits content into lines without their newlines, so a final
+// line does not count as changed when only its newline was added.
+func contentLines(content string) []string {
+	lines := diff.SplitLines(content)
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\n")
+	}
+	return lines
+}
+
+// GetMaterialBlame attributes each line of the main version's content to the
+// version that last changed it. The versions the material had as main are
+// replayed in the order they became main: lines a version keeps carry their
+// attribution over, lines it inserts or changes are attributed to it. Newer
+// versions that main was moved back from are not replayed, so they are never
+// blamed for lines main does not get from them.
+func (s *Services) GetMaterialBlame(ctx context.Context, materialID int64) (models.MaterialBlame, error) {
+	material, err := s.repos.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		return models.MaterialBlame{}, err
+	}
+	if !material.CurrentVersionID.Valid {
+		return models.MaterialBlame{}, customerrors.ErrNotFound
+	}
+
+	history, err := s.repos.ListMaterialMainVersionHistory(ctx, materialID)
+	if err != nil {
+		return models.MaterialBlame{}, err
+	}
+	// Every change of main is recorded, so the history ends with the current
+	// version; should it not, main is added so the blame still describes it
+	if len(history) == 0 || history[len(history)-1].ID != material.CurrentVersionID.Int64 {
+		versions, err := s.repos.ListMaterialVersionsByMaterialID(ctx, materialID)
+		if err != nil {
+			return models.MaterialBlame{}, err
+		}
+		mainIndex := slices.IndexFunc(versions, func(v queries.ListMaterialVersionsByMaterialIDRow) bool {
+			return v.ID == material.CurrentVersionID.Int64
+		})
+		if mainIndex < 0 {
+			return models.MaterialBlame{}, customerrors.ErrNotFound
+		}
+		history = append(history, versions[mainIndex])
+	}
+	main := history[len(history)-1]
+
+	return models.MaterialBlame{
+		MaterialID:    materialID,
+		VersionID:     main.ID,
+		VersionNumber: int(main.VersionNumber),
+		Lines:         blameLines(history),
+	}, nil
+}
+
+// blameLines replays the versions in the order they became main and returns
+// the last one's lines, each attributed to the version that last changed it.
+func blameLines(history []queries.ListMaterialVersionsByMaterialIDRow) []models.MaterialBlameLine {
+	var lines []string
+	var blamed []models.MaterialBlameLine
+	for _, v := range history {
+		next := contentLines(v.Content)
+		nextBlamed := make([]models.MaterialBlameLine, len(next))
+		for _, step := range diff.Tokens(lines, next) {
+			switch step.Op {
+			case diff.OpEqual:
+				nextBlamed[step.B] = blamed[step.A]
+			case diff.OpInsert:
+				nextBlamed[step.B] = models.MaterialBlameLine{
+					VersionID:       v.ID,
+					VersionNumber:   int(v.VersionNumber),
+					AuthorTeacherID: nullInt64ToPointer(v.AuthorTeacherID),
+					CreatedAt:       v.CreatedAt,
+				}
+			}
+		}
+		lines, blamed = next, nextBlamed
+	}
+
+	for i := range blamed {
+		blamed[i].LineNumber = i + 1
+		blamed[i].Text = lines[i]
+	}
+	if blamed == nil {
+		blamed = []models.MaterialBlameLine{}
+	}
+	return blamed
+}
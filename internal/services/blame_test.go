@@ -0,0 +1,71 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+)
+
+func TestBlameLines(t *testing.T) {
+	versions := map[int64]queries.ListMaterialVersionsByMaterialIDRow{
+		1: {ID: 1, VersionNumber: 1, Content: "a\nb\n"},
+		2: {ID: 2, VersionNumber: 2, Content: "a\nB\n"},
+		3: {ID: 3, VersionNumber: 3, Content: "a\nB\nc\n"},
+		// A revert of version 1, copied into a new version
+		4: {ID: 4, VersionNumber: 4, Content: "a\nb\n"},
+	}
+
+	tests := []struct {
+		name string
+		// history is the order the versions became main in
+		history []int64
+		want    []int64
+	}{
+		{
+			name:    "linear history",
+			history: []int64{1, 2, 3},
+			want:    []int64{1, 2, 3},
+		},
+		{
+			name:    "main set back to the previous version",
+			history: []int64{1, 2, 3, 2},
+			want:    []int64{1, 2},
+		},
+		{
+			name:    "main set back to the first version",
+			history: []int64{1, 2, 3, 1},
+			want:    []int64{1, 1},
+		},
+		{
+			name:    "revert blames the lines it changes on the revert",
+			history: []int64{1, 2, 3, 4},
+			want:    []int64{1, 4},
+		},
+		{
+			name:    "revert on top of an older main",
+			history: []int64{1, 2, 3, 2, 4},
+			want:    []int64{1, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := make([]queries.ListMaterialVersionsByMaterialIDRow, len(tt.history))
+			for i, id := range tt.history {
+				history[i] = versions[id]
+			}
+			lines := blameLines(history)
+
+			got := make([]int64, len(lines))
+			for i, line := range lines {
+				got[i] = line.VersionID
+				if line.LineNumber != i+1 {
+					t.Errorf("line %d has number %d", i+1, line.LineNumber)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("lines blamed on versions %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"github.com/didrikolofsson/materials/generated/queries"
+	"github.com/didrikolofsson/materials/internal/diff"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// materialFields holds the editable fields shared by versions and proposals.
+type materialFields struct {
+	Title       string
+	Summary     *string
+	Description *string
+	Content     string
+}
+
+func versionFields(v queries.GetMaterialVersionByIDRow) materialFields {
+	return materialFields{
+		Title:       v.Title,
+		Summary:     nullStringToPointer(v.Summary),
+		Description: nullStringToPointer(v.Description),
+		Content:     v.Content,
+	}
+}
+
+func proposalFields(p queries.GetMaterialProposalByIDRow) materialFields {
+	return materialFields{
+		Title:       p.Title,
+		Summary:     nullStringToPointer(p.Summary),
+		Description: nullStringToPointer(p.Description),
+		Content:     p.Content,
+	}
+}
+
+func diffField(from, to *string) models.FieldDiff {
+	changed := (from == nil) != (to == nil) || (from != nil && *from != *to)
+	return models.FieldDiff{
+		Changed: changed,
+		From:    from,
+		To:      to,
+	}
+}
+
+func diffMaterialFields(from, to materialFields) models.MaterialDiff {
+	return models.MaterialDiff{
+		Title:       diffField(&from.Title, &to.Title),
+		Summary:     diffField(from.Summary, to.Summary),
+		Description: diffField(from.Description, to.Description),
+		Content: models.ContentDiff{
+			Changed: from.Content != to.Content,
+			Lines:   diff.Lines(from.Content, to.Content),
+			Words:   diff.Words(from.Content, to.Content),
+		},
+	}
+}
+
+func sameMaterialFields(a, b materialFields) bool {
+	return a.Content == b.Content &&
+		!diffField(&a.Title, &b.Title).Changed &&
+		!diffField(a.Summary, b.Summary).Changed &&
+		!diffField(a.Description, b.Description).Changed
+}
+
+// mergeField resolves a single field of a three-way merge. It reports a
+// conflict when ours and theirs both changed the base to different values.
+func mergeField(name string, base, ours, theirs *string) (*string, *models.MergeConflict) {
+	switch {
+	case !diffField(base, ours).Changed:
+		return theirs, nil
+	case !diffField(base, theirs).Changed, !diffField(ours, theirs).Changed:
+		return ours, nil
+	}
+	return ours, &models.MergeConflict{
+		Field:  name,
+		Base:   base,
+		Ours:   ours,
+		Theirs: theirs,
+	}
+}
+
+// mergeMaterialFields three-way merges two edits of the same base. Title,
+// summary and description are merged as whole values and content line by
+// line. On conflict the merged content contains conflict markers.
+func mergeMaterialFields(base, ours, theirs materialFields, oursLabel, theirsLabel string) (materialFields, []models.MergeConflict) {
+	conflicts := []models.MergeConflict{}
+	var merged materialFields
+
+	title, conflict := mergeField("title", &base.Title, &ours.Title, &theirs.Title)
+	if conflict != nil {
+		conflicts = append(conflicts, *conflict)
+	}
+	merged.Title = *title
+
+	merged.Summary, conflict = mergeField("summary", base.Summary, ours.Summary, theirs.Summary)
+	if conflict != nil {
+		conflicts = append(conflicts, *conflict)
+	}
+
+	merged.Description, conflict = mergeField("description", base.Description, ours.Description, theirs.Description)
+	if conflict != nil {
+		conflicts = append(conflicts, *conflict)
+	}
+
+	content := diff.Merge(base.Content, ours.Content, theirs.Content, oursLabel, theirsLabel)
+	for _, c := range content.Conflicts {
+		conflicts = append(conflicts, models.MergeConflict{
+			Field:  "content",
+			Line:   c.Line,
+			Base:   &c.Base,
+			Ours:   &c.Ours,
+			Theirs: &c.Theirs,
+		})
+	}
+	merged.Content = content.Text
+
+	return merged, conflicts
+}
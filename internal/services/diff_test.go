@@ -0,0 +1,158 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+func TestMergeMaterialFields(t *testing.T) {
+	str := func(s string) *string { return &s }
+	base := materialFields{
+		Title:       "Base",
+		Summary:     str("base summary"),
+		Description: nil,
+		Content:     "a\nb\nc\n",
+	}
+	// with returns base with change applied
+	with := func(change func(*materialFields)) materialFields {
+		f := base
+		change(&f)
+		return f
+	}
+
+	tests := []struct {
+		name          string
+		ours, theirs  materialFields
+		want          materialFields
+		wantConflicts []models.MergeConflict
+	}{
+		{
+			name:   "nothing changed",
+			ours:   base,
+			theirs: base,
+			want:   base,
+		},
+		{
+			name:   "only ours changed",
+			ours:   with(func(f *materialFields) { f.Title = "Ours"; f.Summary = nil }),
+			theirs: base,
+			want:   with(func(f *materialFields) { f.Title = "Ours"; f.Summary = nil }),
+		},
+		{
+			name:   "only theirs changed",
+			ours:   base,
+			theirs: with(func(f *materialFields) { f.Description = str("added") }),
+			want:   with(func(f *materialFields) { f.Description = str("added") }),
+		},
+		{
+			name:   "each side changed a different field",
+			ours:   with(func(f *materialFields) { f.Title = "Ours" }),
+			theirs: with(func(f *materialFields) { f.Summary = str("theirs summary") }),
+			want: with(func(f *materialFields) {
+				f.Title = "Ours"
+				f.Summary = str("theirs summary")
+			}),
+		},
+		{
+			name:   "both changed a field to the same value",
+			ours:   with(func(f *materialFields) { f.Title = "Same"; f.Description = str("same") }),
+			theirs: with(func(f *materialFields) { f.Title = "Same"; f.Description = str("same") }),
+			want:   with(func(f *materialFields) { f.Title = "Same"; f.Description = str("same") }),
+		},
+		{
+			name:   "both changed a field to different values",
+			ours:   with(func(f *materialFields) { f.Title = "Ours" }),
+			theirs: with(func(f *materialFields) { f.Title = "Theirs" }),
+			want:   with(func(f *materialFields) { f.Title = "Ours" }),
+			wantConflicts: []models.MergeConflict{
+				{Field: "title", Base: str("Base"), Ours: str("Ours"), Theirs: str("Theirs")},
+			},
+		},
+		{
+			name:   "one side cleared a field the other changed",
+			ours:   with(func(f *materialFields) { f.Summary = nil }),
+			theirs: with(func(f *materialFields) { f.Summary = str("theirs summary") }),
+			want:   with(func(f *materialFields) { f.Summary = nil }),
+			wantConflicts: []models.MergeConflict{
+				{Field: "summary", Base: str("base summary"), Ours: nil, Theirs: str("theirs summary")},
+			},
+		},
+		{
+			name:   "content changes to different lines merge",
+			ours:   with(func(f *materialFields) { f.Content = "A\nb\nc\n" }),
+			theirs: with(func(f *materialFields) { f.Content = "a\nb\nC\n" }),
+			want:   with(func(f *materialFields) { f.Content = "A\nb\nC\n" }),
+		},
+		{
+			name:   "content changes to the same line conflict",
+			ours:   with(func(f *materialFields) { f.Content = "a\nours\nc\n" }),
+			theirs: with(func(f *materialFields) { f.Content = "a\ntheirs\nc\n" }),
+			want: with(func(f *materialFields) {
+				f.Content = "a\n<<<<<<< main\nours\n=======\ntheirs\n>>>>>>> update\nc\n"
+			}),
+			wantConflicts: []models.MergeConflict{
+				{Field: "content", Line: 2, Base: str("b\n"), Ours: str("ours\n"), Theirs: str("theirs\n")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := mergeMaterialFields(base, tt.ours, tt.theirs, "main", "update")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("merged = %+v, want %+v", got, tt.want)
+			}
+			want := tt.wantConflicts
+			if want == nil {
+				want = []models.MergeConflict{}
+			}
+			if !reflect.DeepEqual(conflicts, want) {
+				t.Errorf("conflicts = %+v, want %+v", conflicts, want)
+			}
+		})
+	}
+}
+
+// TestMergeMaterialUpdate covers how MergeTeacherMaterialUpdate builds its
+// side of the merge: fields the update leaves out keep main's changes.
+func TestMergeMaterialUpdate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	base := materialFields{Title: "Base", Content: "a\nb\n"}
+	main := materialFields{Title: "Main", Content: "a\nb\n"}
+
+	tests := []struct {
+		name          string
+		req           models.UpdateMaterialRequest
+		want          materialFields
+		wantConflicts int
+	}{
+		{
+			name: "update leaves the title out",
+			req:  models.UpdateMaterialRequest{Content: str("a\nB\n")},
+			want: materialFields{Title: "Main", Content: "a\nB\n"},
+		},
+		{
+			name: "update sets main's title",
+			req:  models.UpdateMaterialRequest{Title: str("Main")},
+			want: main,
+		},
+		{
+			name:          "update sets another title",
+			req:           models.UpdateMaterialRequest{Title: str("Update")},
+			want:          main,
+			wantConflicts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := mergeMaterialFields(base, main, applyMaterialUpdate(base, tt.req), "main", "update")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("merged = %+v, want %+v", got, tt.want)
+			}
+			if len(conflicts) != tt.wantConflicts {
+				t.Errorf("got %d conflicts, want %d: %+v", len(conflicts), tt.wantConflicts, conflicts)
+			}
+		})
+	}
+}
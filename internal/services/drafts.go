@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// getMaterialDraft loads a draft of the material. Published versions and
+// drafts of other materials read as not found.
+func (s *Services) getMaterialDraft(ctx context.Context, materialID, draftID int64) (queries.GetMaterialVersionByIDRow, error) {
+	draft, err := s.repos.GetMaterialVersionByID(ctx, draftID)
+	if err != nil {
+		return queries.GetMaterialVersionByIDRow{}, err
+	}
+	if draft.MaterialID != materialID || !draft.IsDraft {
+		return queries.GetMaterialVersionByIDRow{}, customerrors.ErrNotFound
+	}
+	return draft, nil
+}
+
+// applyMaterialUpdate returns fields with the values set in req replacing
+// the current ones.
+func applyMaterialUpdate(fields materialFields, req models.UpdateMaterialRequest) materialFields {
+	if req.Title != nil {
+		fields.Title = *req.Title
+	}
+	if req.Summary != nil {
+		fields.Summary = req.Summary
+	}
+	if req.Description != nil {
+		fields.Description = req.Description
+	}
+	if req.Content != nil {
+		fields.Content = *req.Content
+	}
+	return fields
+}
+
+// CreateMaterialDraft starts a draft from the material's main version with
+// the requested changes applied. Drafts are not visible outside the owner's
+// draft list until they are published.
+func (s *Services) CreateMaterialDraft(ctx context.Context, teacherID, materialID int64, req models.UpdateMaterialRequest) (models.MaterialVersion, error) {
+	if _, err := s.repos.GetTeacherMaterialByID(ctx, teacherID, materialID); err != nil {
+		return models.MaterialVersion{}, err
+	}
+	if err := s.checkTeacherActive(ctx, teacherID); err != nil {
+		return models.MaterialVersion{}, err
+	}
+	material, err := s.repos.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+	if !material.CurrentVersionID.Valid {
+		return models.MaterialVersion{}, customerrors.ErrBadRequest
+	}
+	main, err := s.repos.GetMaterialVersionByID(ctx, material.CurrentVersionID.Int64)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+
+	fields := applyMaterialUpdate(versionFields(main), req)
+	draftID, err := s.repos.CreateMaterialDraft(
+		ctx, materialID, teacherID, main.ID, fields.Title, fields.Summary, fields.Description, fields.Content, req.ChangeNote,
+	)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+
+	draft, err := s.repos.GetMaterialVersionByID(ctx, draftID)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+	return toMaterialVersion(draft), nil
+}
+
+func (s *Services) ListMaterialDrafts(ctx context.Context, teacherID, materialID int64) ([]models.MaterialVersion, error) {
+	if _, err := s.repos.GetTeacherMaterialByID(ctx, teacherID, materialID); err != nil {
+		return nil, err
+	}
+	res, err := s.repos.ListMaterialDraftsByMaterialID(ctx, materialID)
+	if err != nil {
+		return nil, err
+	}
+	drafts := make([]models.MaterialVersion, len(res))
+	for i, draft := range res {
+		drafts[i] = toMaterialVersion(draft)
+	}
+	return drafts, nil
+}
+
+// UpdateMaterialDraft edits a draft in place; unlike material updates it
+// does not create a new version. It returns ErrConflict if the draft was
+// published or discarded in the meantime.
+func (s *Services) UpdateMaterialDraft(ctx context.Context, teacherID, materialID, draftID int64, req models.UpdateMaterialRequest) (models.MaterialVersion, error) {
+	if _, err := s.repos.GetTeacherMaterialByID(ctx, teacherID, materialID); err != nil {
+		return models.MaterialVersion{}, err
+	}
+	if err := s.checkTeacherActive(ctx, teacherID); err != nil {
+		return models.MaterialVersion{}, err
+	}
+	draft, err := s.getMaterialDraft(ctx, materialID, draftID)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+
+	fields := applyMaterialUpdate(versionFields(draft), req)
+	changeNote := nullStringToPointer(draft.ChangeNote)
+	if req.ChangeNote != nil {
+		changeNote = req.ChangeNote
+	}
+	// An unchanged draft would not touch the row, which the repository reads
+	// as the draft having been published or discarded in the meantime
+	if sameMaterialFields(fields, versionFields(draft)) &&
+		!diffField(changeNote, nullStringToPointer(draft.ChangeNote)).Changed {
+		return toMaterialVersion(draft), nil
+	}
+	err = s.repos.UpdateMaterialDraft(
+		ctx, draft.ID, fields.Title, fields.Summary, fields.Description, fields.Content, changeNote,
+	)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+
+	updated, err := s.repos.GetMaterialVersionByID(ctx, draft.ID)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+	return toMaterialVersion(updated), nil
+}
+
+// PublishMaterialDraft makes a draft the material's main version, provided
+// the material's current version is one of ifMatch; nil accepts any. Passing
+// the draft's base version refuses to replace edits published since the
+// draft was started.
+func (s *Services) PublishMaterialDraft(ctx context.Context, teacherID, materialID, draftID int64, ifMatch []int64) (models.Material, error) {
+	if _, err := s.repos.GetTeacherMaterialByID(ctx, teacherID, materialID); err != nil {
+		return models.Material{}, err
+	}
+	if err := s.checkTeacherActive(ctx, teacherID); err != nil {
+		return models.Material{}, err
+	}
+	draft, err := s.getMaterialDraft(ctx, materialID, draftID)
+	if err != nil {
+		return models.Material{}, err
+	}
+
+	if err = s.repos.PublishMaterialDraft(ctx, materialID, draft, ifMatch); err != nil {
+		return models.Material{}, err
+	}
+
+	return s.GetTeacherMaterialByID(ctx, teacherID, materialID)
+}
+
+func (s *Services) DeleteMaterialDraft(ctx context.Context, teacherID, materialID, draftID int64) error {
+	if _, err := s.repos.GetTeacherMaterialByID(ctx, teacherID, materialID); err != nil {
+		return err
+	}
+	if err := s.checkTeacherActive(ctx, teacherID); err != nil {
+		return err
+	}
+	if _, err := s.getMaterialDraft(ctx, materialID, draftID); err != nil {
+		return err
+	}
+	return s.repos.DeleteMaterialDraft(ctx, draftID)
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// ForkMaterial copies a material's main version into a new material owned by
+// the requesting teacher. The fork keeps the source's subject unless another
+// one is given.
+func (s *Services) ForkMaterial(ctx context.Context, materialID int64, req models.ForkMaterialRequest) (int64, error) {
+	source, err := s.repos.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		return 0, err
+	}
+	if source.TeacherID == req.TeacherID {
+		return 0, customerrors.ErrBadRequest
+	}
+	if !source.CurrentVersionID.Valid {
+		return 0, customerrors.ErrConflict
+	}
+	// The fork would be owned by the teacher and so never editable
+	if err = s.checkTeacherActive(ctx, req.TeacherID); err != nil {
+		return 0, err
+	}
+
+	subjectID := nullInt64ToPointer(source.SubjectID)
+	if req.SubjectID != nil {
+		if _, err = s.repos.GetSubjectByID(ctx, *req.SubjectID); err != nil {
+			return 0, err
+		}
+		subjectID = req.SubjectID
+	}
+
+	main, err := s.repos.GetMaterialVersionByID(ctx, source.CurrentVersionID.Int64)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.repos.ForkMaterial(ctx, source, main, req.TeacherID, subjectID)
+}
+
+// GetMaterialLineage returns the chain of originals a material was forked
+// from and the tree of forks made from it.
+func (s *Services) GetMaterialLineage(ctx context.Context, materialID int64) (models.MaterialLineage, error) {
+	if _, err := s.repos.GetMaterialByID(ctx, materialID); err != nil {
+		return models.MaterialLineage{}, err
+	}
+
+	ancestors, err := s.repos.ListMaterialAncestors(ctx, materialID)
+	if err != nil {
+		return models.MaterialLineage{}, err
+	}
+	descendants, err := s.repos.ListMaterialDescendants(ctx, materialID)
+	if err != nil {
+		return models.MaterialLineage{}, err
+	}
+
+	lineage := models.MaterialLineage{
+		MaterialID: materialID,
+		Ancestors:  make([]models.MaterialLineageNode, len(ancestors)),
+	}
+	for i, a := range ancestors {
+		lineage.Ancestors[i] = models.MaterialLineageNode{
+			ID:                 a.ID,
+			TeacherID:          a.TeacherID,
+			TeacherName:        a.TeacherName,
+			OriginalMaterialID: nullInt64ToPointer(a.OriginalMaterialID),
+			Title:              nullStringToPointer(a.Title),
+			CreatedAt:          a.CreatedAt,
+		}
+	}
+
+	forksOf := make(map[int64][]models.MaterialLineageNode)
+	for _, d := range descendants {
+		parentID := d.OriginalMaterialID.Int64
+		forksOf[parentID] = append(forksOf[parentID], models.MaterialLineageNode{
+			ID:                 d.ID,
+			TeacherID:          d.TeacherID,
+			TeacherName:        d.TeacherName,
+			OriginalMaterialID: nullInt64ToPointer(d.OriginalMaterialID),
+			Title:              nullStringToPointer(d.Title),
+			CreatedAt:          d.CreatedAt,
+		})
+	}
+	var attach func(id int64) []models.MaterialLineageNode
+	attach = func(id int64) []models.MaterialLineageNode {
+		forks := forksOf[id]
+		for i := range forks {
+			forks[i].Forks = attach(forks[i].ID)
+		}
+		return forks
+	}
+	lineage.Forks = attach(materialID)
+	if lineage.Forks == nil {
+		lineage.Forks = []models.MaterialLineageNode{}
+	}
+
+	return lineage, nil
+}
+
+// isFork reports whether a material was forked from another one. Seeded
+// materials point at themselves, which does not count.
+func isFork(m queries.Material) bool {
+	return m.OriginalMaterialID.Valid && m.OriginalMaterialID.Int64 != m.ID
+}
+
+// forkUpstream works out the upstream state of a fork, also returning the
+// synced and latest upstream versions when they exist.
+func (s *Services) forkUpstream(ctx context.Context, fork queries.Material) (models.MaterialUpstream, *queries.GetMaterialVersionByIDRow, *queries.GetMaterialVersionByIDRow, error) {
+	if !isFork(fork) {
+		return models.MaterialUpstream{}, nil, nil, customerrors.ErrBadRequest
+	}
+	original, err := s.repos.GetMaterialByID(ctx, fork.OriginalMaterialID.Int64)
+	if err != nil {
+		return models.MaterialUpstream{}, nil, nil, err
+	}
+
+	upstream := models.MaterialUpstream{
+		MaterialID:         fork.ID,
+		OriginalMaterialID: original.ID,
+	}
+
+	var synced, latest *queries.GetMaterialVersionByIDRow
+	var syncedNumber int32
+	if fork.UpstreamVersionID.Valid {
+		version, err := s.repos.GetMaterialVersionByID(ctx, fork.UpstreamVersionID.Int64)
+		if err != nil {
+			return models.MaterialUpstream{}, nil, nil, err
+		}
+		number := int(version.VersionNumber)
+		synced = &version
+		syncedNumber = version.VersionNumber
+		upstream.SyncedVersionID = &version.ID
+		upstream.SyncedVersionNumber = &number
+	}
+	if original.CurrentVersionID.Valid {
+		version, err := s.repos.GetMaterialVersionByID(ctx, original.CurrentVersionID.Int64)
+		if err != nil {
+			return models.MaterialUpstream{}, nil, nil, err
+		}
+		number := int(version.VersionNumber)
+		latest = &version
+		upstream.LatestVersionID = &version.ID
+		upstream.LatestVersionNumber = &number
+	}
+
+	upstream.BehindBy, err = s.repos.CountMaterialVersionsAfter(ctx, original.ID, syncedNumber)
+	if err != nil {
+		return models.MaterialUpstream{}, nil, nil, err
+	}
+	return upstream, synced, latest, nil
+}
+
+func (s *Services) GetMaterialUpstream(ctx context.Context, materialID int64) (models.MaterialUpstream, error) {
+	fork, err := s.repos.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		return models.MaterialUpstream{}, err
+	}
+	upstream, _, _, err := s.forkUpstream(ctx, fork)
+	return upstream, err
+}
+
+// SyncMaterialFork pulls the changes made upstream since the fork was last
+// synced into the fork. Upstream edits are three-way merged with the fork's
+// own edits, using the last synced upstream version as the base. Nothing is
+// saved when the merge conflicts; the conflicts are returned together with
+// ErrConflict instead.
+func (s *Services) SyncMaterialFork(ctx context.Context, materialID int64, req models.SyncMaterialForkRequest) (models.MaterialForkSync, error) {
+	fork, err := s.repos.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		return models.MaterialForkSync{}, err
+	}
+	if fork.TeacherID != req.TeacherID {
+		return models.MaterialForkSync{}, customerrors.ErrForbidden
+	}
+	if err = s.checkTeacherActive(ctx, fork.TeacherID); err != nil {
+		return models.MaterialForkSync{}, err
+	}
+	upstream, synced, latest, err := s.forkUpstream(ctx, fork)
+	if err != nil {
+		return models.MaterialForkSync{}, err
+	}
+	if latest == nil || (synced != nil && synced.ID == latest.ID) {
+		return models.MaterialForkSync{
+			Upstream:  upstream,
+			Conflicts: []models.MergeConflict{},
+		}, nil
+	}
+	if !fork.CurrentVersionID.Valid {
+		return models.MaterialForkSync{}, customerrors.ErrConflict
+	}
+
+	ours, err := s.repos.GetMaterialVersionByID(ctx, fork.CurrentVersionID.Int64)
+	if err != nil {
+		return models.MaterialForkSync{}, err
+	}
+	// Without a synced version every difference counts as an edit on both sides
+	var base materialFields
+	if synced != nil {
+		base = versionFields(*synced)
+	}
+
+	merged, conflicts := mergeMaterialFields(base, versionFields(ours), versionFields(*latest), "fork", "upstream")
+	if len(conflicts) > 0 {
+		return models.MaterialForkSync{
+			Upstream:      upstream,
+			Conflicts:     conflicts,
+			MergedContent: &merged.Content,
+		}, customerrors.ErrConflict
+	}
+
+	var versionID *int64
+	if sameMaterialFields(merged, versionFields(ours)) {
+		err = s.repos.UpdateMaterialUpstreamVersion(ctx, fork.ID, ours.ID, latest.ID)
+	} else {
+		var id int64
+		id, err = s.repos.SyncMaterialFork(
+			ctx,
+			fork.ID,
+			req.TeacherID,
+			ours.ID,
+			latest.ID,
+			merged.Title,
+			merged.Summary,
+			merged.Description,
+			merged.Content,
+			fmt.Sprintf("Synced with material %d v%d", upstream.OriginalMaterialID, latest.VersionNumber),
+		)
+		versionID = &id
+	}
+	if err != nil {
+		return models.MaterialForkSync{}, err
+	}
+
+	upstream, err = s.GetMaterialUpstream(ctx, materialID)
+	if err != nil {
+		return models.MaterialForkSync{}, err
+	}
+	return models.MaterialForkSync{
+		Upstream:  upstream,
+		VersionID: versionID,
+		Conflicts: conflicts,
+	}, nil
+}
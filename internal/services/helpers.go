@@ -1,6 +1,9 @@
 package services
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+)
 
 func nullStringToPointer(s sql.NullString) *string {
 	if s.Valid {
@@ -15,3 +18,25 @@ func int64PtrToValue(ptr *int64) int64 {
 	}
 	return 0
 }
+
+func nullInt64ToPointer(i sql.NullInt64) *int64 {
+	if i.Valid {
+		return &i.Int64
+	}
+	return nil
+}
+
+func nullTimeToPointer(t sql.NullTime) *time.Time {
+	if t.Valid {
+		return &t.Time
+	}
+	return nil
+}
+
+func nullInt32ToIntPointer(i sql.NullInt32) *int {
+	if i.Valid {
+		v := int(i.Int32)
+		return &v
+	}
+	return nil
+}
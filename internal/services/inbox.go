@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"slices"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// proposalStatuses lists every proposal status so counts include zeroes.
+var proposalStatuses = []queries.MaterialProposalsStatus{
+	queries.MaterialProposalsStatusPENDING,
+	queries.MaterialProposalsStatusAPPROVED,
+	queries.MaterialProposalsStatusREJECTED,
+	queries.MaterialProposalsStatusWITHDRAWN,
+	queries.MaterialProposalsStatusSUPERSEDED,
+	queries.MaterialProposalsStatusEXPIRED,
+}
+
+func newProposalStatusCounts() map[string]int64 {
+	counts := make(map[string]int64, len(proposalStatuses))
+	for _, status := range proposalStatuses {
+		counts[string(status)] = 0
+	}
+	return counts
+}
+
+// GetTeacherInbox returns the pending proposals on materials the teacher owns
+// and every proposal the teacher has authored, oldest first unless
+// newestFirst is set.
+func (s *Services) GetTeacherInbox(ctx context.Context, teacherID int64, newestFirst bool) (models.TeacherInbox, error) {
+	if _, err := s.repos.GetTeacherByID(ctx, teacherID); err != nil {
+		return models.TeacherInbox{}, err
+	}
+
+	inbox := models.TeacherInbox{
+		TeacherID: teacherID,
+		Review: models.InboxSection{
+			Counts:    newProposalStatusCounts(),
+			Proposals: []models.MaterialProposal{},
+		},
+		Authored: models.InboxSection{
+			Counts:    newProposalStatusCounts(),
+			Proposals: []models.MaterialProposal{},
+		},
+	}
+
+	review, err := s.repos.ListPendingMaterialProposalsByOwnerTeacherID(ctx, teacherID)
+	if err != nil {
+		return models.TeacherInbox{}, err
+	}
+	for _, row := range review {
+		inbox.Review.Proposals = append(inbox.Review.Proposals, toMaterialProposal(row, row.CurrentVersionID))
+	}
+
+	authored, err := s.repos.ListMaterialProposalsByAuthorTeacherID(ctx, teacherID)
+	if err != nil {
+		return models.TeacherInbox{}, err
+	}
+	for _, row := range authored {
+		inbox.Authored.Proposals = append(inbox.Authored.Proposals, toMaterialProposal(row, row.CurrentVersionID))
+	}
+
+	reviewCounts, err := s.repos.CountMaterialProposalsByOwnerTeacherID(ctx, teacherID)
+	if err != nil {
+		return models.TeacherInbox{}, err
+	}
+	for _, count := range reviewCounts {
+		inbox.Review.Counts[string(count.Status)] = count.Count
+	}
+
+	authoredCounts, err := s.repos.CountMaterialProposalsByAuthorTeacherID(ctx, teacherID)
+	if err != nil {
+		return models.TeacherInbox{}, err
+	}
+	for _, count := range authoredCounts {
+		inbox.Authored.Counts[string(count.Status)] = count.Count
+	}
+
+	// Queries return the oldest proposals first
+	if newestFirst {
+		slices.Reverse(inbox.Review.Proposals)
+		slices.Reverse(inbox.Authored.Proposals)
+	}
+
+	return inbox, nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	"github.com/didrikolofsson/materials/internal/diff"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+func toMaterialProposalComment(c queries.MaterialProposalComment) models.MaterialProposalComment {
+	return models.MaterialProposalComment{
+		ID:                  c.ID,
+		ProposalID:          c.ProposalID,
+		AuthorTeacherID:     c.AuthorTeacherID,
+		ParentCommentID:     nullInt64ToPointer(c.ParentCommentID),
+		LineStart:           nullInt32ToIntPointer(c.LineStart),
+		LineEnd:             nullInt32ToIntPointer(c.LineEnd),
+		Body:                c.Body,
+		ResolvedByTeacherID: nullInt64ToPointer(c.ResolvedByTeacherID),
+		ResolvedAt:          nullTimeToPointer(c.ResolvedAt),
+		CreatedAt:           c.CreatedAt,
+	}
+}
+
+// ListMaterialProposalComments returns the comment threads of a proposal in
+// the order they were started, each with its replies.
+func (s *Services) ListMaterialProposalComments(ctx context.Context, proposalID int64) ([]models.MaterialProposalComment, error) {
+	if _, err := s.repos.GetMaterialProposalByID(ctx, proposalID); err != nil {
+		return nil, err
+	}
+
+	res, err := s.repos.ListMaterialProposalCommentsByProposalID(ctx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	threads := []models.MaterialProposalComment{}
+	threadIndex := make(map[int64]int)
+	for _, comment := range res {
+		if !comment.ParentCommentID.Valid {
+			threadIndex[comment.ID] = len(threads)
+			threads = append(threads, toMaterialProposalComment(comment))
+		}
+	}
+	for _, comment := range res {
+		if !comment.ParentCommentID.Valid {
+			continue
+		}
+		if i, ok := threadIndex[comment.ParentCommentID.Int64]; ok {
+			threads[i].Replies = append(threads[i].Replies, toMaterialProposalComment(comment))
+		}
+	}
+	return threads, nil
+}
+
+func (s *Services) CreateMaterialProposalComment(ctx context.Context, proposalID int64, req models.CreateMaterialProposalCommentRequest) (models.MaterialProposalComment, error) {
+	proposal, err := s.repos.GetMaterialProposalByID(ctx, proposalID)
+	if err != nil {
+		return models.MaterialProposalComment{}, err
+	}
+	if _, err = s.repos.GetTeacherByID(ctx, req.AuthorTeacherID); err != nil {
+		return models.MaterialProposalComment{}, err
+	}
+
+	if req.ParentCommentID != nil {
+		// Replies belong to a thread and share its anchor
+		if req.LineStart != nil {
+			return models.MaterialProposalComment{}, customerrors.ErrBadRequest
+		}
+		parent, err := s.repos.GetMaterialProposalCommentByID(ctx, *req.ParentCommentID)
+		if err != nil {
+			return models.MaterialProposalComment{}, err
+		}
+		if parent.ProposalID != proposalID || parent.ParentCommentID.Valid {
+			return models.MaterialProposalComment{}, customerrors.ErrBadRequest
+		}
+	} else if req.LineStart != nil {
+		lines := len(diff.SplitLines(proposal.Content))
+		if *req.LineEnd < *req.LineStart || *req.LineEnd > lines {
+			return models.MaterialProposalComment{}, customerrors.ErrBadRequest
+		}
+	}
+
+	commentID, err := s.repos.CreateMaterialProposalComment(ctx, proposalID, req)
+	if err != nil {
+		return models.MaterialProposalComment{}, err
+	}
+	comment, err := s.repos.GetMaterialProposalCommentByID(ctx, commentID)
+	if err != nil {
+		return models.MaterialProposalComment{}, err
+	}
+	return toMaterialProposalComment(comment), nil
+}
+
+func (s *Services) ResolveMaterialProposalComment(ctx context.Context, proposalID, commentID int64, req models.ResolveMaterialProposalCommentRequest) (models.MaterialProposalComment, error) {
+	return s.setMaterialProposalCommentResolved(ctx, proposalID, commentID, req.TeacherID, true)
+}
+
+func (s *Services) ReopenMaterialProposalComment(ctx context.Context, proposalID, commentID int64, req models.ResolveMaterialProposalCommentRequest) (models.MaterialProposalComment, error) {
+	return s.setMaterialProposalCommentResolved(ctx, proposalID, commentID, req.TeacherID, false)
+}
+
+// setMaterialProposalCommentResolved resolves or reopens a thread. The
+// proposal's owner and author and the teacher who started the thread may do so.
+func (s *Services) setMaterialProposalCommentResolved(ctx context.Context, proposalID, commentID, teacherID int64, resolved bool) (models.MaterialProposalComment, error) {
+	proposal, err := s.repos.GetMaterialProposalByID(ctx, proposalID)
+	if err != nil {
+		return models.MaterialProposalComment{}, err
+	}
+	comment, err := s.repos.GetMaterialProposalCommentByID(ctx, commentID)
+	if err != nil {
+		return models.MaterialProposalComment{}, err
+	}
+	if comment.ProposalID != proposalID {
+		return models.MaterialProposalComment{}, customerrors.ErrNotFound
+	}
+	if comment.ParentCommentID.Valid {
+		return models.MaterialProposalComment{}, customerrors.ErrBadRequest
+	}
+	if teacherID != proposal.OwnerTeacherID &&
+		teacherID != proposal.AuthorTeacherID &&
+		teacherID != comment.AuthorTeacherID {
+		return models.MaterialProposalComment{}, customerrors.ErrForbidden
+	}
+
+	if resolved {
+		err = s.repos.ResolveMaterialProposalComment(ctx, commentID, teacherID)
+	} else {
+		err = s.repos.ReopenMaterialProposalComment(ctx, commentID)
+	}
+	if err != nil {
+		return models.MaterialProposalComment{}, err
+	}
+
+	comment, err = s.repos.GetMaterialProposalCommentByID(ctx, commentID)
+	if err != nil {
+		return models.MaterialProposalComment{}, err
+	}
+	return toMaterialProposalComment(comment), nil
+}
@@ -0,0 +1,443 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// toMaterialProposal converts a proposal row. A pending proposal is stale when
+// the material's current version is no longer the version it was based on.
+func toMaterialProposal(p queries.GetMaterialProposalByIDRow, currentVersionID sql.NullInt64) models.MaterialProposal {
+	return models.MaterialProposal{
+		ID:                     p.ID,
+		MaterialID:             p.MaterialID,
+		MaterialVersionID:      p.MaterialVersionID,
+		OwnerTeacherID:         p.OwnerTeacherID,
+		AuthorTeacherID:        p.AuthorTeacherID,
+		Title:                  p.Title,
+		Summary:                nullStringToPointer(p.Summary),
+		Description:            nullStringToPointer(p.Description),
+		Content:                p.Content,
+		Status:                 string(p.Status),
+		DecidedByTeacherID:     nullInt64ToPointer(p.DecidedByTeacherID),
+		DecidedAt:              nullTimeToPointer(p.DecidedAt),
+		CreatedAt:              p.CreatedAt,
+		SupersededByProposalID: nullInt64ToPointer(p.SupersededByProposalID),
+		RemindedAt:             nullTimeToPointer(p.RemindedAt),
+		Stale: p.Status == queries.MaterialProposalsStatusPENDING &&
+			currentVersionID.Valid &&
+			currentVersionID.Int64 != p.MaterialVersionID,
+	}
+}
+
+func (s *Services) CreateMaterialProposal(ctx context.Context, materialID int64, req models.CreateMaterialProposalRequest) (int64, error) {
+	material, err := s.repos.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Owners edit their materials directly, proposals are for other teachers
+	if material.TeacherID == req.AuthorTeacherID {
+		return 0, customerrors.ErrForbidden
+	}
+
+	if err = s.checkTeacherActive(ctx, req.AuthorTeacherID); err != nil {
+		return 0, err
+	}
+	if err = s.checkTeacherActive(ctx, material.TeacherID); err != nil {
+		return 0, err
+	}
+
+	// The proposal must be based on a published version of this material, the
+	// owner's drafts are private
+	if _, err = s.getMaterialVersion(ctx, materialID, req.MaterialVersionID); err != nil {
+		return 0, err
+	}
+
+	return s.repos.CreateMaterialProposal(ctx, materialID, material.TeacherID, req)
+}
+
+func (s *Services) GetMaterialProposalByID(ctx context.Context, id int64) (models.MaterialProposal, error) {
+	proposal, err := s.repos.GetMaterialProposalByID(ctx, id)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+	material, err := s.repos.GetMaterialByID(ctx, proposal.MaterialID)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+	return toMaterialProposal(proposal, material.CurrentVersionID), nil
+}
+
+func (s *Services) ListMaterialProposalsByMaterialID(ctx context.Context, materialID int64) ([]models.MaterialProposal, error) {
+	material, err := s.repos.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.repos.ListMaterialProposalsByMaterialID(ctx, materialID)
+	if err != nil {
+		return nil, err
+	}
+	proposals := make([]models.MaterialProposal, len(res))
+	for i, proposal := range res {
+		proposals[i] = toMaterialProposal(proposal, material.CurrentVersionID)
+	}
+	return proposals, nil
+}
+
+// proposalTransitions lists the statuses a proposal may move to from each
+// status. Only pending proposals can change, every other status is final.
+var proposalTransitions = map[queries.MaterialProposalsStatus][]queries.MaterialProposalsStatus{
+	queries.MaterialProposalsStatusPENDING: {
+		queries.MaterialProposalsStatusAPPROVED,
+		queries.MaterialProposalsStatusREJECTED,
+		queries.MaterialProposalsStatusWITHDRAWN,
+		queries.MaterialProposalsStatusSUPERSEDED,
+		queries.MaterialProposalsStatusEXPIRED,
+	},
+}
+
+// checkProposalTransition verifies that the proposal may move to the given
+// status. Only authors withdraw; who may approve and reject is decided by the
+// material's review policy.
+func checkProposalTransition(proposal queries.GetMaterialProposalByIDRow, to queries.MaterialProposalsStatus, teacherID int64) error {
+	if to == queries.MaterialProposalsStatusWITHDRAWN && proposal.AuthorTeacherID != teacherID {
+		return customerrors.ErrForbidden
+	}
+	if !slices.Contains(proposalTransitions[proposal.Status], to) {
+		return customerrors.ErrConflict
+	}
+	return nil
+}
+
+// checkProposalEditable verifies that the teacher is the proposal's author
+// and that the proposal is still pending.
+func checkProposalEditable(proposal queries.GetMaterialProposalByIDRow, teacherID int64) error {
+	if proposal.AuthorTeacherID != teacherID {
+		return customerrors.ErrForbidden
+	}
+	if proposal.Status != queries.MaterialProposalsStatusPENDING {
+		return customerrors.ErrBadRequest
+	}
+	return nil
+}
+
+// ApproveMaterialProposal records the teacher's approval. When the material's
+// review policy is satisfied the proposal is merged into a new main version.
+func (s *Services) ApproveMaterialProposal(ctx context.Context, proposalID int64, req models.DecideMaterialProposalRequest) (models.MaterialProposal, error) {
+	proposal, err := s.repos.GetMaterialProposalByID(ctx, proposalID)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+	material, err := s.repos.GetMaterialByID(ctx, proposal.MaterialID)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+	// Approving may merge the proposal into the material
+	if err = s.checkTeacherActive(ctx, req.TeacherID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+	if err = s.checkTeacherActive(ctx, material.TeacherID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+	policy, err := s.reviewPolicyForMaterial(ctx, material)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+
+	if !canApproveProposal(policy, proposal, req.TeacherID) {
+		return models.MaterialProposal{}, customerrors.ErrForbidden
+	}
+	if err = checkProposalTransition(proposal, queries.MaterialProposalsStatusAPPROVED, req.TeacherID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+
+	// A stale proposal would overwrite newer edits on main, it must be rebased first
+	if toMaterialProposal(proposal, material.CurrentVersionID).Stale {
+		return models.MaterialProposal{}, customerrors.ErrConflict
+	}
+
+	// Record the approval and, once enough are in, create the new main version
+	// in the same transaction. A repeated approval is rejected there, where
+	// the proposal is locked.
+	_, err = s.repos.ApproveMaterialProposal(ctx, proposal, req.TeacherID, policy.RequiredApprovals)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+
+	return s.GetMaterialProposalByID(ctx, proposalID)
+}
+
+func (s *Services) RejectMaterialProposal(ctx context.Context, proposalID int64, req models.DecideMaterialProposalRequest) (models.MaterialProposal, error) {
+	proposal, err := s.repos.GetMaterialProposalByID(ctx, proposalID)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+	material, err := s.repos.GetMaterialByID(ctx, proposal.MaterialID)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+	// Rejecting leaves the material alone, so only the acting teacher must be
+	// active
+	if err = s.checkTeacherActive(ctx, req.TeacherID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+	policy, err := s.reviewPolicyForMaterial(ctx, material)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+
+	if !canRejectProposal(policy, proposal, req.TeacherID) {
+		return models.MaterialProposal{}, customerrors.ErrForbidden
+	}
+	if err = checkProposalTransition(proposal, queries.MaterialProposalsStatusREJECTED, req.TeacherID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+
+	if err = s.repos.RejectMaterialProposal(ctx, proposalID, req.TeacherID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+
+	return s.GetMaterialProposalByID(ctx, proposalID)
+}
+
+// DiffMaterialProposal compares a proposal with the version it was based on
+// and with the material's current main version.
+func (s *Services) DiffMaterialProposal(ctx context.Context, proposalID int64) (models.MaterialProposalDiff, error) {
+	proposal, err := s.repos.GetMaterialProposalByID(ctx, proposalID)
+	if err != nil {
+		return models.MaterialProposalDiff{}, err
+	}
+	material, err := s.repos.GetMaterialByID(ctx, proposal.MaterialID)
+	if err != nil {
+		return models.MaterialProposalDiff{}, err
+	}
+
+	base, err := s.getMaterialVersion(ctx, proposal.MaterialID, proposal.MaterialVersionID)
+	if err != nil {
+		return models.MaterialProposalDiff{}, err
+	}
+	main := base
+	if material.CurrentVersionID.Valid && material.CurrentVersionID.Int64 != base.ID {
+		main, err = s.repos.GetMaterialVersionByID(ctx, material.CurrentVersionID.Int64)
+		if err != nil {
+			return models.MaterialProposalDiff{}, err
+		}
+	}
+
+	proposed := proposalFields(proposal)
+	return models.MaterialProposalDiff{
+		ProposalID:    proposal.ID,
+		BaseVersionID: base.ID,
+		MainVersionID: main.ID,
+		Base:          diffMaterialFields(versionFields(base), proposed),
+		Main:          diffMaterialFields(versionFields(main), proposed),
+	}, nil
+}
+
+// RebaseMaterialProposal moves a stale proposal onto the material's current
+// main version by three-way merging the proposal with the changes made on
+// main since the proposal's base. Nothing is saved when the merge conflicts;
+// the conflicts are returned together with ErrConflict instead.
+func (s *Services) RebaseMaterialProposal(ctx context.Context, proposalID int64, req models.RebaseMaterialProposalRequest) (models.MaterialProposalRebase, error) {
+	proposal, err := s.repos.GetMaterialProposalByID(ctx, proposalID)
+	if err != nil {
+		return models.MaterialProposalRebase{}, err
+	}
+	if err = checkProposalEditable(proposal, req.TeacherID); err != nil {
+		return models.MaterialProposalRebase{}, err
+	}
+
+	material, err := s.repos.GetMaterialByID(ctx, proposal.MaterialID)
+	if err != nil {
+		return models.MaterialProposalRebase{}, err
+	}
+	if err = s.checkTeacherActive(ctx, req.TeacherID); err != nil {
+		return models.MaterialProposalRebase{}, err
+	}
+	if err = s.checkTeacherActive(ctx, material.TeacherID); err != nil {
+		return models.MaterialProposalRebase{}, err
+	}
+	current := toMaterialProposal(proposal, material.CurrentVersionID)
+	if !current.Stale {
+		return models.MaterialProposalRebase{
+			Proposal:  current,
+			Conflicts: []models.MergeConflict{},
+		}, nil
+	}
+
+	base, err := s.getMaterialVersion(ctx, proposal.MaterialID, proposal.MaterialVersionID)
+	if err != nil {
+		return models.MaterialProposalRebase{}, err
+	}
+	main, err := s.repos.GetMaterialVersionByID(ctx, material.CurrentVersionID.Int64)
+	if err != nil {
+		return models.MaterialProposalRebase{}, err
+	}
+
+	merged, conflicts := mergeMaterialFields(
+		versionFields(base),
+		versionFields(main),
+		proposalFields(proposal),
+		"main",
+		"proposal",
+	)
+	if len(conflicts) > 0 {
+		return models.MaterialProposalRebase{
+			Proposal:      current,
+			Conflicts:     conflicts,
+			MergedContent: &merged.Content,
+		}, customerrors.ErrConflict
+	}
+
+	err = s.repos.ReviseMaterialProposal(
+		ctx,
+		proposal.ID,
+		main.ID,
+		merged.Title,
+		merged.Summary,
+		merged.Description,
+		merged.Content,
+	)
+	if err != nil {
+		return models.MaterialProposalRebase{}, err
+	}
+
+	rebased, err := s.GetMaterialProposalByID(ctx, proposalID)
+	if err != nil {
+		return models.MaterialProposalRebase{}, err
+	}
+	return models.MaterialProposalRebase{
+		Proposal:  rebased,
+		Conflicts: conflicts,
+	}, nil
+}
+
+func (s *Services) WithdrawMaterialProposal(ctx context.Context, proposalID int64, req models.WithdrawMaterialProposalRequest) (models.MaterialProposal, error) {
+	proposal, err := s.repos.GetMaterialProposalByID(ctx, proposalID)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+	if err = checkProposalTransition(proposal, queries.MaterialProposalsStatusWITHDRAWN, req.TeacherID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+
+	if err = s.repos.WithdrawMaterialProposal(ctx, proposalID, req.TeacherID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+
+	return s.GetMaterialProposalByID(ctx, proposalID)
+}
+
+// ReviseMaterialProposal updates a pending proposal in place, keeping its
+// base version. The previous revisions stay available in its history.
+func (s *Services) ReviseMaterialProposal(ctx context.Context, proposalID int64, req models.ReviseMaterialProposalRequest) (models.MaterialProposal, error) {
+	proposal, err := s.repos.GetMaterialProposalByID(ctx, proposalID)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+	if err = checkProposalEditable(proposal, req.AuthorTeacherID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+	if err = s.checkTeacherActive(ctx, req.AuthorTeacherID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+	if err = s.checkTeacherActive(ctx, proposal.OwnerTeacherID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+	if _, err = s.getMaterialVersion(ctx, proposal.MaterialID, proposal.MaterialVersionID); err != nil {
+		return models.MaterialProposal{}, err
+	}
+
+	// Use provided values or defaults from the current revision
+	revised := proposalFields(proposal)
+	if req.Title != nil {
+		revised.Title = *req.Title
+	}
+	if req.Summary != nil {
+		revised.Summary = req.Summary
+	}
+	if req.Description != nil {
+		revised.Description = req.Description
+	}
+	if req.Content != nil {
+		revised.Content = *req.Content
+	}
+	// An unchanged revision would not touch the row, which the repository
+	// reads as the proposal having been decided in the meantime
+	if sameMaterialFields(revised, proposalFields(proposal)) {
+		return s.GetMaterialProposalByID(ctx, proposalID)
+	}
+
+	err = s.repos.ReviseMaterialProposal(
+		ctx,
+		proposal.ID,
+		proposal.MaterialVersionID,
+		revised.Title,
+		revised.Summary,
+		revised.Description,
+		revised.Content,
+	)
+	if err != nil {
+		return models.MaterialProposal{}, err
+	}
+
+	return s.GetMaterialProposalByID(ctx, proposalID)
+}
+
+func (s *Services) ListMaterialProposalRevisions(ctx context.Context, proposalID int64) ([]models.MaterialProposalRevision, error) {
+	if _, err := s.repos.GetMaterialProposalByID(ctx, proposalID); err != nil {
+		return nil, err
+	}
+
+	res, err := s.repos.ListMaterialProposalRevisionsByProposalID(ctx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]models.MaterialProposalRevision, len(res))
+	for i, revision := range res {
+		revisions[i] = models.MaterialProposalRevision{
+			ID:                revision.ID,
+			ProposalID:        revision.ProposalID,
+			RevisionNumber:    int(revision.RevisionNumber),
+			MaterialVersionID: revision.MaterialVersionID,
+			Title:             revision.Title,
+			Summary:           nullStringToPointer(revision.Summary),
+			Description:       nullStringToPointer(revision.Description),
+			Content:           revision.Content,
+			CreatedAt:         revision.CreatedAt,
+		}
+	}
+	return revisions, nil
+}
+
+// ListIdleMaterialProposals returns pending proposals that have not been
+// created or reminded about since idleSince, oldest first.
+func (s *Services) ListIdleMaterialProposals(ctx context.Context, idleSince time.Time) ([]models.MaterialProposal, error) {
+	res, err := s.repos.ListIdleMaterialProposals(ctx, idleSince)
+	if err != nil {
+		return nil, err
+	}
+	proposals := make([]models.MaterialProposal, len(res))
+	for i, row := range res {
+		proposals[i] = toMaterialProposal(row, row.CurrentVersionID)
+	}
+	return proposals, nil
+}
+
+func (s *Services) MarkMaterialProposalReminded(ctx context.Context, proposalID int64) error {
+	return s.repos.MarkMaterialProposalReminded(ctx, proposalID)
+}
+
+// ExpireMaterialProposals closes every pending proposal created before the
+// given time and returns how many were closed.
+func (s *Services) ExpireMaterialProposals(ctx context.Context, createdBefore time.Time) (int64, error) {
+	return s.repos.ExpireMaterialProposals(ctx, createdBefore)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// GetProposalReport summarises proposals created in [from, to) per authoring
+// teacher and per subject. Either bound may be nil to leave it open.
+func (s *Services) GetProposalReport(ctx context.Context, from, to *time.Time) (models.ProposalReport, error) {
+	report := models.ProposalReport{
+		From:     from,
+		To:       to,
+		Teachers: []models.TeacherProposalStats{},
+		Subjects: []models.SubjectProposalStats{},
+	}
+
+	teacherCounts, teacherMedians, err := s.repos.ReportMaterialProposalsByTeacher(ctx, from, to)
+	if err != nil {
+		return models.ProposalReport{}, err
+	}
+	medianByTeacher := make(map[int64]int64, len(teacherMedians))
+	for _, median := range teacherMedians {
+		medianByTeacher[median.TeacherID] = median.MedianSeconds
+	}
+	for _, row := range teacherCounts {
+		stats := models.TeacherProposalStats{
+			TeacherID:   row.TeacherID,
+			TeacherName: row.TeacherName,
+			ProposalStats: models.ProposalStats{
+				Opened:   row.Opened,
+				Approved: row.Approved,
+				Rejected: row.Rejected,
+			},
+		}
+		if median, ok := medianByTeacher[row.TeacherID]; ok {
+			stats.MedianTimeToDecisionSeconds = &median
+		}
+		report.Teachers = append(report.Teachers, stats)
+	}
+
+	subjectCounts, subjectMedians, err := s.repos.ReportMaterialProposalsBySubject(ctx, from, to)
+	if err != nil {
+		return models.ProposalReport{}, err
+	}
+	// Materials without a subject are reported under subject ID 0
+	medianBySubject := make(map[int64]int64, len(subjectMedians))
+	for _, median := range subjectMedians {
+		medianBySubject[median.SubjectID.Int64] = median.MedianSeconds
+	}
+	for _, row := range subjectCounts {
+		stats := models.SubjectProposalStats{
+			SubjectID:   nullInt64ToPointer(row.SubjectID),
+			SubjectName: nullStringToPointer(row.SubjectName),
+			ProposalStats: models.ProposalStats{
+				Opened:   row.Opened,
+				Approved: row.Approved,
+				Rejected: row.Rejected,
+			},
+		}
+		if median, ok := medianBySubject[row.SubjectID.Int64]; ok {
+			stats.MedianTimeToDecisionSeconds = &median
+		}
+		report.Subjects = append(report.Subjects, stats)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// VersionRetentionPolicy decides which published versions of a material are
+// kept. Versions younger than DailyAfter are always kept; older ones are
+// thinned to the newest version of each day unless another rule keeps them.
+// The main version, the last version each day left main at and versions
+// referenced by proposals or forks are never deleted, and drafts are not
+// subject to retention.
+type VersionRetentionPolicy struct {
+	// KeepLast keeps the newest versions of each material regardless of age
+	KeepLast int
+	// KeepTagged keeps every version that has a tag
+	KeepTagged bool
+	// DailyAfter is the age after which only one version per day is kept
+	DailyAfter time.Duration
+}
+
+// retiredVersions returns the IDs of the versions the policy would delete.
+// versions must belong to one material and be ordered newest first.
+func (p VersionRetentionPolicy) retiredVersions(versions []queries.ListMaterialVersionsForRetentionRow, now time.Time) []int64 {
+	cutoff := now.Add(-p.DailyAfter)
+	keptDays := make(map[string]bool)
+
+	var retired []int64
+	for i, v := range versions {
+		// Recent history is kept in full
+		if !v.CreatedAt.Before(cutoff) {
+			continue
+		}
+		kept := v.IsMain ||
+			v.LastMainOfDay ||
+			v.ReferencedByProposal ||
+			v.ReferencedByRevision ||
+			v.ReferencedByMaterial ||
+			i < p.KeepLast ||
+			(p.KeepTagged && v.Tagged)
+
+		// Newest first, so the first version seen on a day is its last one.
+		// A version kept by another rule also counts as its day's version.
+		day := v.CreatedAt.UTC().Format(time.DateOnly)
+		if kept || !keptDays[day] {
+			keptDays[day] = true
+			continue
+		}
+		retired = append(retired, v.ID)
+	}
+	return retired
+}
+
+// ApplyVersionRetention deletes the versions the policy does not keep,
+// material by material, and then the content bodies nothing references any
+// more. With dryRun set nothing is deleted and the result reports the
+// versions that would have been.
+func (s *Services) ApplyVersionRetention(ctx context.Context, policy VersionRetentionPolicy, now time.Time, dryRun bool) (models.VersionRetentionResult, error) {
+	versions, err := s.repos.ListMaterialVersionsForRetention(ctx)
+	if err != nil {
+		return models.VersionRetentionResult{}, err
+	}
+
+	result := models.VersionRetentionResult{
+		DryRun:          dryRun,
+		VersionsScanned: len(versions),
+	}
+	for start := 0; start < len(versions); {
+		end := start
+		for end < len(versions) && versions[end].MaterialID == versions[start].MaterialID {
+			end++
+		}
+		result.Materials++
+
+		retired := policy.retiredVersions(versions[start:end], now)
+		start = end
+		if len(retired) == 0 {
+			continue
+		}
+		if dryRun {
+			result.VersionsDeleted += int64(len(retired))
+			continue
+		}
+		deleted, err := s.repos.DeleteRetiredMaterialVersions(ctx, retired)
+		// The material changed since it was listed; the next run retries it
+		if errors.Is(err, customerrors.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		result.VersionsDeleted += deleted
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	contents, err := s.repos.DeleteOrphanedMaterialContents(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.ContentsDeleted = contents
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+)
+
+func TestRetiredVersions(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	policy := VersionRetentionPolicy{DailyAfter: 7 * 24 * time.Hour}
+	// oldDay is well past DailyAfter
+	oldDay := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// version returns an unreferenced version created at the hour of day
+	version := func(id int64, day time.Time, hour int) queries.ListMaterialVersionsForRetentionRow {
+		return queries.ListMaterialVersionsForRetentionRow{
+			ID:            id,
+			MaterialID:    1,
+			VersionNumber: int32(id),
+			CreatedAt:     day.Add(time.Duration(hour) * time.Hour),
+		}
+	}
+	// oldDayVersions returns the newest version of oldDay followed by an
+	// earlier one changed by keep, which the policy would otherwise retire
+	oldDayVersions := func(keep func(*queries.ListMaterialVersionsForRetentionRow)) []queries.ListMaterialVersionsForRetentionRow {
+		earlier := version(1, oldDay, 9)
+		keep(&earlier)
+		return []queries.ListMaterialVersionsForRetentionRow{version(2, oldDay, 15), earlier}
+	}
+
+	tests := []struct {
+		name     string
+		policy   VersionRetentionPolicy
+		versions []queries.ListMaterialVersionsForRetentionRow
+		want     []int64
+	}{
+		{
+			name:   "recent versions are kept in full",
+			policy: policy,
+			versions: []queries.ListMaterialVersionsForRetentionRow{
+				version(3, now.Add(-3*time.Hour), 0),
+				version(2, now.Add(-4*time.Hour), 0),
+				version(1, now.Add(-5*time.Hour), 0),
+			},
+		},
+		{
+			name:   "old days keep their newest version",
+			policy: policy,
+			versions: []queries.ListMaterialVersionsForRetentionRow{
+				version(5, now.Add(-time.Hour), 0),
+				version(4, oldDay.AddDate(0, 0, 1), 8),
+				version(3, oldDay, 20),
+				version(2, oldDay, 15),
+				version(1, oldDay, 9),
+			},
+			want: []int64{2, 1},
+		},
+		{
+			name:     "unreferenced earlier version is retired",
+			policy:   policy,
+			versions: oldDayVersions(func(*queries.ListMaterialVersionsForRetentionRow) {}),
+			want:     []int64{1},
+		},
+		{
+			name:     "main version is kept",
+			policy:   policy,
+			versions: oldDayVersions(func(v *queries.ListMaterialVersionsForRetentionRow) { v.IsMain = true }),
+		},
+		{
+			name:     "last main version of a day is kept",
+			policy:   policy,
+			versions: oldDayVersions(func(v *queries.ListMaterialVersionsForRetentionRow) { v.LastMainOfDay = true }),
+		},
+		{
+			name:     "version referenced by a proposal is kept",
+			policy:   policy,
+			versions: oldDayVersions(func(v *queries.ListMaterialVersionsForRetentionRow) { v.ReferencedByProposal = true }),
+		},
+		{
+			name:     "version referenced by a revision is kept",
+			policy:   policy,
+			versions: oldDayVersions(func(v *queries.ListMaterialVersionsForRetentionRow) { v.ReferencedByRevision = true }),
+		},
+		{
+			name:     "version referenced by a material is kept",
+			policy:   policy,
+			versions: oldDayVersions(func(v *queries.ListMaterialVersionsForRetentionRow) { v.ReferencedByMaterial = true }),
+		},
+		{
+			name:     "tagged version is kept with KeepTagged",
+			policy:   VersionRetentionPolicy{KeepTagged: true, DailyAfter: policy.DailyAfter},
+			versions: oldDayVersions(func(v *queries.ListMaterialVersionsForRetentionRow) { v.Tagged = true }),
+		},
+		{
+			name:     "tagged version is retired without KeepTagged",
+			policy:   policy,
+			versions: oldDayVersions(func(v *queries.ListMaterialVersionsForRetentionRow) { v.Tagged = true }),
+			want:     []int64{1},
+		},
+		{
+			name:   "newest versions are kept with KeepLast",
+			policy: VersionRetentionPolicy{KeepLast: 2, DailyAfter: policy.DailyAfter},
+			versions: []queries.ListMaterialVersionsForRetentionRow{
+				version(3, oldDay, 20),
+				version(2, oldDay, 15),
+				version(1, oldDay, 9),
+			},
+			want: []int64{1},
+		},
+		{
+			name:   "a version kept by another rule is its day's version",
+			policy: policy,
+			versions: []queries.ListMaterialVersionsForRetentionRow{
+				func() queries.ListMaterialVersionsForRetentionRow {
+					v := version(2, oldDay, 15)
+					v.IsMain = true
+					return v
+				}(),
+				version(1, oldDay, 9),
+			},
+			want: []int64{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.retiredVersions(tt.versions, now)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("retiredVersions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+const (
+	reviewPolicySourceMaterial = "material"
+	reviewPolicySourceSubject  = "subject"
+	reviewPolicySourceDefault  = "default"
+)
+
+func toReviewPolicy(p queries.ReviewPolicy, reviewerIDs []int64, source string) models.ReviewPolicy {
+	if reviewerIDs == nil {
+		reviewerIDs = []int64{}
+	}
+	return models.ReviewPolicy{
+		Source:              source,
+		MaterialID:          nullInt64ToPointer(p.MaterialID),
+		SubjectID:           nullInt64ToPointer(p.SubjectID),
+		RequiredApprovals:   int(p.RequiredApprovals),
+		OwnerCanSelfApprove: p.OwnerCanSelfApprove,
+		ReviewerTeacherIDs:  reviewerIDs,
+	}
+}
+
+// defaultReviewPolicy applies when neither the material nor its subject has
+// a policy: the owner alone approves.
+func defaultReviewPolicy() models.ReviewPolicy {
+	return models.ReviewPolicy{
+		Source:              reviewPolicySourceDefault,
+		RequiredApprovals:   1,
+		OwnerCanSelfApprove: true,
+		ReviewerTeacherIDs:  []int64{},
+	}
+}
+
+// reviewPolicyForMaterial resolves the policy of a material, falling back to
+// its subject's policy and then to the default.
+func (s *Services) reviewPolicyForMaterial(ctx context.Context, material queries.Material) (models.ReviewPolicy, error) {
+	policy, reviewers, err := s.repos.GetMaterialReviewPolicy(ctx, material.ID)
+	if err == nil {
+		return toReviewPolicy(policy, reviewers, reviewPolicySourceMaterial), nil
+	}
+	if !errors.Is(err, customerrors.ErrNotFound) {
+		return models.ReviewPolicy{}, err
+	}
+
+	if material.SubjectID.Valid {
+		policy, reviewers, err = s.repos.GetSubjectReviewPolicy(ctx, material.SubjectID.Int64)
+		if err == nil {
+			return toReviewPolicy(policy, reviewers, reviewPolicySourceSubject), nil
+		}
+		if !errors.Is(err, customerrors.ErrNotFound) {
+			return models.ReviewPolicy{}, err
+		}
+	}
+
+	return defaultReviewPolicy(), nil
+}
+
+// canApproveProposal reports whether the teacher's approval counts towards
+// the policy. Authors never approve their own proposals.
+func canApproveProposal(policy models.ReviewPolicy, proposal queries.GetMaterialProposalByIDRow, teacherID int64) bool {
+	if teacherID == proposal.AuthorTeacherID {
+		return false
+	}
+	if teacherID == proposal.OwnerTeacherID {
+		return policy.OwnerCanSelfApprove
+	}
+	return slices.Contains(policy.ReviewerTeacherIDs, teacherID)
+}
+
+// canRejectProposal reports whether the teacher may reject a proposal. The
+// owner always can, as can every reviewer named by the policy.
+func canRejectProposal(policy models.ReviewPolicy, proposal queries.GetMaterialProposalByIDRow, teacherID int64) bool {
+	return teacherID == proposal.OwnerTeacherID || slices.Contains(policy.ReviewerTeacherIDs, teacherID)
+}
+
+// checkReviewers verifies that the reviewers exist and that enough teachers
+// can approve to ever satisfy the policy.
+func (s *Services) checkReviewers(ctx context.Context, requiredApprovals int, ownerCanSelfApprove bool, reviewerIDs []int64) error {
+	for _, reviewerID := range reviewerIDs {
+		if _, err := s.repos.GetTeacherByID(ctx, reviewerID); err != nil {
+			if errors.Is(err, customerrors.ErrNotFound) {
+				return customerrors.ErrBadRequest
+			}
+			return err
+		}
+	}
+
+	approvers := len(reviewerIDs)
+	if ownerCanSelfApprove {
+		approvers++
+	}
+	if approvers < requiredApprovals {
+		return customerrors.ErrBadRequest
+	}
+	return nil
+}
+
+func (s *Services) GetMaterialReviewPolicy(ctx context.Context, materialID int64) (models.ReviewPolicy, error) {
+	material, err := s.repos.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		return models.ReviewPolicy{}, err
+	}
+	return s.reviewPolicyForMaterial(ctx, material)
+}
+
+func (s *Services) SetMaterialReviewPolicy(ctx context.Context, materialID int64, req models.SetMaterialReviewPolicyRequest) (models.ReviewPolicy, error) {
+	material, err := s.repos.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		return models.ReviewPolicy{}, err
+	}
+	if material.TeacherID != req.TeacherID {
+		return models.ReviewPolicy{}, customerrors.ErrForbidden
+	}
+	if err = s.checkTeacherActive(ctx, material.TeacherID); err != nil {
+		return models.ReviewPolicy{}, err
+	}
+
+	// The owner approves through owner_can_self_approve, not as a reviewer
+	reviewerIDs := slices.DeleteFunc(slices.Clone(req.ReviewerTeacherIDs), func(id int64) bool {
+		return id == material.TeacherID
+	})
+	if err = s.checkReviewers(ctx, req.RequiredApprovals, req.OwnerCanSelfApprove, reviewerIDs); err != nil {
+		return models.ReviewPolicy{}, err
+	}
+
+	err = s.repos.SetMaterialReviewPolicy(ctx, materialID, int32(req.RequiredApprovals), req.OwnerCanSelfApprove, reviewerIDs)
+	if err != nil {
+		return models.ReviewPolicy{}, err
+	}
+	return s.reviewPolicyForMaterial(ctx, material)
+}
+
+func (s *Services) GetSubjectReviewPolicy(ctx context.Context, subjectID int64) (models.ReviewPolicy, error) {
+	if _, err := s.repos.GetSubjectByID(ctx, subjectID); err != nil {
+		return models.ReviewPolicy{}, err
+	}
+
+	policy, reviewers, err := s.repos.GetSubjectReviewPolicy(ctx, subjectID)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNotFound) {
+			return defaultReviewPolicy(), nil
+		}
+		return models.ReviewPolicy{}, err
+	}
+	return toReviewPolicy(policy, reviewers, reviewPolicySourceSubject), nil
+}
+
+func (s *Services) SetSubjectReviewPolicy(ctx context.Context, subjectID int64, req models.SetSubjectReviewPolicyRequest) (models.ReviewPolicy, error) {
+	if _, err := s.repos.GetSubjectByID(ctx, subjectID); err != nil {
+		return models.ReviewPolicy{}, err
+	}
+	if err := s.checkReviewers(ctx, req.RequiredApprovals, req.OwnerCanSelfApprove, req.ReviewerTeacherIDs); err != nil {
+		return models.ReviewPolicy{}, err
+	}
+
+	err := s.repos.SetSubjectReviewPolicy(ctx, subjectID, int32(req.RequiredApprovals), req.OwnerCanSelfApprove, req.ReviewerTeacherIDs)
+	if err != nil {
+		return models.ReviewPolicy{}, err
+	}
+	return s.GetSubjectReviewPolicy(ctx, subjectID)
+}
+
+func (s *Services) ListMaterialProposalApprovals(ctx context.Context, proposalID int64) (models.MaterialProposalApprovals, error) {
+	proposal, err := s.repos.GetMaterialProposalByID(ctx, proposalID)
+	if err != nil {
+		return models.MaterialProposalApprovals{}, err
+	}
+	material, err := s.repos.GetMaterialByID(ctx, proposal.MaterialID)
+	if err != nil {
+		return models.MaterialProposalApprovals{}, err
+	}
+	policy, err := s.reviewPolicyForMaterial(ctx, material)
+	if err != nil {
+		return models.MaterialProposalApprovals{}, err
+	}
+
+	res, err := s.repos.ListMaterialProposalApprovals(ctx, proposalID)
+	if err != nil {
+		return models.MaterialProposalApprovals{}, err
+	}
+	approvals := make([]models.MaterialProposalApproval, len(res))
+	for i, approval := range res {
+		approvals[i] = models.MaterialProposalApproval{
+			TeacherID: approval.TeacherID,
+			CreatedAt: approval.CreatedAt,
+		}
+	}
+
+	return models.MaterialProposalApprovals{
+		ProposalID: proposalID,
+		Policy:     policy,
+		Approvals:  approvals,
+	}, nil
+}
@@ -30,11 +30,7 @@ func (s *Services) ListTeachers(ctx context.Context) ([]models.Teacher, error) {
 	}
 	teachers := make([]models.Teacher, len(res))
 	for i, teacher := range res {
-		teachers[i] = models.Teacher{
-			ID:        teacher.ID,
-			Name:      teacher.Name,
-			CreatedAt: teacher.CreatedAt,
-		}
+		teachers[i] = toTeacher(teacher)
 	}
 	return teachers, nil
 }
@@ -46,11 +42,7 @@ func (s *Services) ListSubjects(ctx context.Context) ([]models.Subject, error) {
 	}
 	subjects := make([]models.Subject, len(res))
 	for i, subject := range res {
-		subjects[i] = models.Subject{
-			ID:        subject.ID,
-			Name:      subject.Name,
-			CreatedAt: subject.CreatedAt,
-		}
+		subjects[i] = toSubject(subject)
 	}
 	return subjects, nil
 }
@@ -63,13 +55,14 @@ func (s *Services) ListMaterials(ctx context.Context) ([]models.Material, error)
 	materials := make([]models.Material, len(res))
 	for i, material := range res {
 		materials[i] = models.Material{
-			ID:          material.ID,
-			TeacherName: material.TeacherName,
-			SubjectName: material.SubjectName,
-			CreatedAt:   material.CreatedAt,
-			Title:       material.Title,
-			Description: nullStringToPointer(material.Description),
-			Summary:     nullStringToPointer(material.Summary),
+			ID:               material.ID,
+			TeacherName:      material.TeacherName,
+			SubjectName:      material.SubjectName,
+			CreatedAt:        material.CreatedAt,
+			Title:            material.Title,
+			Description:      nullStringToPointer(material.Description),
+			Summary:          nullStringToPointer(material.Summary),
+			CurrentVersionID: material.CurrentVersionID.Int64,
 		}
 	}
 	return materials, nil
@@ -83,14 +76,16 @@ func (s *Services) ListMaterialVersionsByMaterialID(ctx context.Context, materia
 	materialVersions := make([]models.MaterialVersion, len(res))
 	for i, materialVersion := range res {
 		materialVersions[i] = models.MaterialVersion{
-			ID:            materialVersion.ID,
-			Title:         materialVersion.Title,
-			Description:   nullStringToPointer(materialVersion.Description),
-			Summary:       nullStringToPointer(materialVersion.Summary),
-			Content:       materialVersion.Content,
-			VersionNumber: int(materialVersion.VersionNumber),
-			IsMain:        materialVersion.IsMain,
-			CreatedAt:     materialVersion.CreatedAt,
+			ID:              materialVersion.ID,
+			Title:           materialVersion.Title,
+			Description:     nullStringToPointer(materialVersion.Description),
+			Summary:         nullStringToPointer(materialVersion.Summary),
+			Content:         materialVersion.Content,
+			VersionNumber:   int(materialVersion.VersionNumber),
+			IsMain:          materialVersion.IsMain,
+			CreatedAt:       materialVersion.CreatedAt,
+			ChangeNote:      nullStringToPointer(materialVersion.ChangeNote),
+			AuthorTeacherID: nullInt64ToPointer(materialVersion.AuthorTeacherID),
 		}
 	}
 	return materialVersions, nil
@@ -101,11 +96,7 @@ func (s *Services) GetTeacherByID(ctx context.Context, id int64) (models.Teacher
 	if err != nil {
 		return models.Teacher{}, err
 	}
-	return models.Teacher{
-		ID:        teacher.ID,
-		Name:      teacher.Name,
-		CreatedAt: teacher.CreatedAt,
-	}, nil
+	return toTeacher(teacher), nil
 }
 
 func (s *Services) GetTeacherMaterials(ctx context.Context, teacherID int64) ([]models.Material, error) {
@@ -116,13 +107,14 @@ func (s *Services) GetTeacherMaterials(ctx context.Context, teacherID int64) ([]
 	materials := make([]models.Material, len(res))
 	for i, material := range res {
 		materials[i] = models.Material{
-			ID:          material.ID,
-			TeacherName: material.TeacherName,
-			SubjectName: material.SubjectName,
-			CreatedAt:   material.CreatedAt,
-			Title:       material.Title,
-			Description: nullStringToPointer(material.Description),
-			Summary:     nullStringToPointer(material.Summary),
+			ID:               material.ID,
+			TeacherName:      material.TeacherName,
+			SubjectName:      material.SubjectName,
+			CreatedAt:        material.CreatedAt,
+			Title:            material.Title,
+			Description:      nullStringToPointer(material.Description),
+			Summary:          nullStringToPointer(material.Summary),
+			CurrentVersionID: material.CurrentVersionID.Int64,
 		}
 	}
 	return materials, nil
@@ -134,17 +126,23 @@ func (s *Services) GetTeacherMaterialByID(ctx context.Context, teacherID, materi
 		return models.Material{}, err
 	}
 	return models.Material{
-		ID:          material.ID,
-		TeacherName: material.TeacherName,
-		SubjectName: material.SubjectName,
-		CreatedAt:   material.CreatedAt,
-		Title:       material.Title,
-		Description: nullStringToPointer(material.Description),
-		Summary:     nullStringToPointer(material.Summary),
+		ID:               material.ID,
+		TeacherName:      material.TeacherName,
+		SubjectName:      material.SubjectName,
+		CreatedAt:        material.CreatedAt,
+		Title:            material.Title,
+		Description:      nullStringToPointer(material.Description),
+		Summary:          nullStringToPointer(material.Summary),
+		CurrentVersionID: material.CurrentVersionID.Int64,
 	}, nil
 }
 
 func (s *Services) CreateInitialTeacherMaterial(ctx context.Context, teacherID int64, req models.CreateMaterialRequest) (int64, error) {
+	// It could never be edited afterwards
+	if err := s.checkTeacherActive(ctx, teacherID); err != nil {
+		return 0, err
+	}
+
 	// Create the material
 	subjectID := int64PtrToValue(req.SubjectID)
 
@@ -161,11 +159,17 @@ func (s *Services) CreateInitialTeacherMaterial(ctx context.Context, teacherID i
 	return materialID, nil
 }
 
-func (s *Services) UpdateTeacherMaterialByID(ctx context.Context, teacherID, materialID int64, req models.UpdateMaterialRequest) (models.Material, error) {
+// UpdateTeacherMaterialByID creates a new main version with the requested
+// changes. The update only applies while the material's current version is
+// one of ifMatch; nil accepts any current version.
+func (s *Services) UpdateTeacherMaterialByID(ctx context.Context, teacherID, materialID int64, ifMatch []int64, req models.UpdateMaterialRequest) (models.Material, error) {
 	_, err := s.repos.GetTeacherMaterialByID(ctx, teacherID, materialID)
 	if err != nil {
 		return models.Material{}, err
 	}
+	if err = s.checkTeacherActive(ctx, teacherID); err != nil {
+		return models.Material{}, err
+	}
 
 	// Get the current version to use as defaults
 	versions, err := s.repos.ListMaterialVersionsByMaterialID(ctx, materialID)
@@ -206,28 +210,82 @@ func (s *Services) UpdateTeacherMaterialByID(ctx context.Context, teacherID, mat
 		content = *req.Content
 	}
 
-	// Create a new version with updated content
-	versionID, err := s.repos.CreateMaterialVersion(
-		ctx, materialID, title, summary, description, content, true,
+	// Create a new version with updated content and make it main
+	_, err = s.repos.CreateMainMaterialVersion(
+		ctx, materialID, teacherID, ifMatch, title, summary, description, content, req.ChangeNote,
 	)
 	if err != nil {
 		return models.Material{}, err
 	}
 
-	// Update all versions to set is_main = false, then set the new one to true
-	err = s.repos.UpdateMaterialVersionMain(ctx, materialID, versionID)
+	// Return the updated material
+	return s.GetTeacherMaterialByID(ctx, teacherID, materialID)
+}
+
+// MergeTeacherMaterialUpdate applies an update that was made against
+// req.BaseVersionID. Changes made on main since then are three-way merged
+// with the update, content line by line and the other fields as whole
+// values. Nothing is saved when the merge conflicts; the conflicts are
+// returned together with ErrConflict instead.
+func (s *Services) MergeTeacherMaterialUpdate(ctx context.Context, teacherID, materialID int64, req models.UpdateMaterialRequest) (models.MaterialMerge, error) {
+	if req.BaseVersionID == nil {
+		return models.MaterialMerge{}, customerrors.ErrBadRequest
+	}
+	if _, err := s.repos.GetTeacherMaterialByID(ctx, teacherID, materialID); err != nil {
+		return models.MaterialMerge{}, err
+	}
+	if err := s.checkTeacherActive(ctx, teacherID); err != nil {
+		return models.MaterialMerge{}, err
+	}
+	material, err := s.repos.GetMaterialByID(ctx, materialID)
 	if err != nil {
-		return models.Material{}, err
+		return models.MaterialMerge{}, err
+	}
+	if !material.CurrentVersionID.Valid {
+		return models.MaterialMerge{}, customerrors.ErrBadRequest
 	}
 
-	// Update the material's current_version_id
-	err = s.repos.UpdateMaterialCurrentVersion(ctx, materialID, versionID)
+	base, err := s.getMaterialVersion(ctx, materialID, *req.BaseVersionID)
 	if err != nil {
-		return models.Material{}, err
+		return models.MaterialMerge{}, err
+	}
+	main, err := s.repos.GetMaterialVersionByID(ctx, material.CurrentVersionID.Int64)
+	if err != nil {
+		return models.MaterialMerge{}, err
 	}
 
-	// Return the updated material
-	return s.GetTeacherMaterialByID(ctx, teacherID, materialID)
+	merge := models.MaterialMerge{
+		BaseVersionID: base.ID,
+		MainVersionID: main.ID,
+		Conflicts:     []models.MergeConflict{},
+	}
+	merged, conflicts := mergeMaterialFields(
+		versionFields(base),
+		versionFields(main),
+		applyMaterialUpdate(versionFields(base), req),
+		"main",
+		"update",
+	)
+	if len(conflicts) > 0 {
+		merge.Conflicts = conflicts
+		merge.MergedContent = &merged.Content
+		return merge, customerrors.ErrConflict
+	}
+
+	// The merge is only valid on top of the main version it was made with
+	_, err = s.repos.CreateMainMaterialVersion(
+		ctx, materialID, teacherID, []int64{main.ID}, merged.Title, merged.Summary, merged.Description, merged.Content, req.ChangeNote,
+	)
+	if err != nil {
+		return models.MaterialMerge{}, err
+	}
+
+	updated, err := s.GetTeacherMaterialByID(ctx, teacherID, materialID)
+	if err != nil {
+		return models.MaterialMerge{}, err
+	}
+	merge.Material = &updated
+	return merge, nil
 }
 
 func (s *Services) DeleteTeacherMaterialByID(ctx context.Context, teacherID, materialID int64) error {
@@ -236,8 +294,11 @@ func (s *Services) DeleteTeacherMaterialByID(ctx context.Context, teacherID, mat
 	if err != nil {
 		return err
 	}
+	if err = s.checkTeacherActive(ctx, teacherID); err != nil {
+		return err
+	}
 
-	// Delete the material (cascade should handle versions)
+	// Delete the material with its proposals; the database cascades the rest
 	err = s.repos.DeleteMaterial(ctx, materialID)
 	if err != nil {
 		return err
@@ -245,16 +306,19 @@ func (s *Services) DeleteTeacherMaterialByID(ctx context.Context, teacherID, mat
 	return nil
 }
 
-func (s *Services) UpdateMaterialVersionMain(ctx context.Context, materialID, versionID int64) error {
-	err := s.repos.UpdateMaterialVersionMain(ctx, materialID, versionID)
-	if err != nil {
+// UpdateMaterialVersionMain makes an existing version main, provided the
+// material's current version is one of ifMatch; nil accepts any.
+func (s *Services) UpdateMaterialVersionMain(ctx context.Context, materialID, versionID int64, ifMatch []int64) error {
+	// Drafts only become main by being published
+	if _, err := s.getMaterialVersion(ctx, materialID, versionID); err != nil {
 		return err
 	}
-
-	// Also update the material's current_version_id
-	err = s.repos.UpdateMaterialCurrentVersion(ctx, materialID, versionID)
+	material, err := s.repos.GetMaterialByID(ctx, materialID)
 	if err != nil {
 		return err
 	}
-	return nil
+	if err = s.checkTeacherActive(ctx, material.TeacherID); err != nil {
+		return err
+	}
+	return s.repos.SetMaterialMainVersion(ctx, materialID, versionID, ifMatch)
 }
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+func toSubject(s queries.Subject) models.Subject {
+	return models.Subject{
+		ID:              s.ID,
+		Name:            s.Name,
+		CreatedAt:       s.CreatedAt,
+		ParentSubjectID: nullInt64ToPointer(s.ParentSubjectID),
+	}
+}
+
+func (s *Services) GetSubjectByID(ctx context.Context, id int64) (models.Subject, error) {
+	subject, err := s.repos.GetSubjectByID(ctx, id)
+	if err != nil {
+		return models.Subject{}, err
+	}
+	return toSubject(subject), nil
+}
+
+func (s *Services) CreateSubject(ctx context.Context, req models.CreateSubjectRequest) (models.Subject, error) {
+	if req.ParentSubjectID != nil {
+		if _, err := s.repos.GetSubjectByID(ctx, *req.ParentSubjectID); err != nil {
+			return models.Subject{}, err
+		}
+	}
+	subjectID, err := s.repos.CreateSubject(ctx, req.Name, req.ParentSubjectID)
+	if err != nil {
+		return models.Subject{}, err
+	}
+	return s.GetSubjectByID(ctx, subjectID)
+}
+
+// UpdateSubject returns ErrBadRequest when the new parent is the subject
+// itself or one of its descendants and ErrConflict when it lost a deadlock
+// to a concurrent change.
+func (s *Services) UpdateSubject(ctx context.Context, subjectID int64, req models.UpdateSubjectRequest) (models.Subject, error) {
+	if err := s.repos.UpdateSubject(ctx, subjectID, req.Name, req.ParentSubjectID); err != nil {
+		return models.Subject{}, err
+	}
+	return s.GetSubjectByID(ctx, subjectID)
+}
+
+// DeleteSubject deletes a subject that has no child subjects. A subject with
+// materials is only deleted when reassignTo names another subject to move
+// them to; otherwise ErrConflict is returned.
+func (s *Services) DeleteSubject(ctx context.Context, subjectID int64, reassignTo *int64) error {
+	if reassignTo != nil && *reassignTo == subjectID {
+		return customerrors.ErrBadRequest
+	}
+	return s.repos.DeleteSubject(ctx, subjectID, reassignTo)
+}
+
+// ListSubjectMaterials lists the materials filed under the subject or any of
+// its descendants.
+func (s *Services) ListSubjectMaterials(ctx context.Context, subjectID int64) ([]models.Material, error) {
+	if _, err := s.repos.GetSubjectByID(ctx, subjectID); err != nil {
+		return nil, err
+	}
+	res, err := s.repos.ListSubjectMaterials(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	materials := make([]models.Material, len(res))
+	for i, material := range res {
+		materials[i] = models.Material{
+			ID:               material.ID,
+			TeacherName:      material.TeacherName,
+			SubjectName:      material.SubjectName,
+			CreatedAt:        material.CreatedAt,
+			Title:            material.Title,
+			Description:      nullStringToPointer(material.Description),
+			Summary:          nullStringToPointer(material.Summary),
+			CurrentVersionID: material.CurrentVersionID.Int64,
+		}
+	}
+	return materials, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+func toMaterialVersionTag(t queries.MaterialVersionTag) models.MaterialVersionTag {
+	return models.MaterialVersionTag{
+		Name:      t.Name,
+		VersionID: t.MaterialVersionID,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// checkMaterialOwner returns ErrForbidden unless teacherID owns the material.
+func (s *Services) checkMaterialOwner(ctx context.Context, materialID, teacherID int64) error {
+	material, err := s.repos.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		return err
+	}
+	if material.TeacherID != teacherID {
+		return customerrors.ErrForbidden
+	}
+	return nil
+}
+
+func (s *Services) ListMaterialVersionTags(ctx context.Context, materialID, versionID int64) ([]models.MaterialVersionTag, error) {
+	if _, err := s.getMaterialVersion(ctx, materialID, versionID); err != nil {
+		return nil, err
+	}
+	res, err := s.repos.ListMaterialVersionTagsByVersionID(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]models.MaterialVersionTag, len(res))
+	for i, tag := range res {
+		tags[i] = toMaterialVersionTag(tag)
+	}
+	return tags, nil
+}
+
+// CreateMaterialVersionTag tags a version. Tag names are unique per material,
+// so a name already used on any version of the material is a conflict.
+func (s *Services) CreateMaterialVersionTag(ctx context.Context, materialID, versionID int64, req models.CreateMaterialVersionTagRequest) (models.MaterialVersionTag, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return models.MaterialVersionTag{}, customerrors.ErrBadRequest
+	}
+	if err := s.checkMaterialOwner(ctx, materialID, req.TeacherID); err != nil {
+		return models.MaterialVersionTag{}, err
+	}
+	if err := s.checkTeacherActive(ctx, req.TeacherID); err != nil {
+		return models.MaterialVersionTag{}, err
+	}
+	if _, err := s.getMaterialVersion(ctx, materialID, versionID); err != nil {
+		return models.MaterialVersionTag{}, err
+	}
+
+	_, err := s.repos.GetMaterialVersionTagByName(ctx, materialID, name)
+	if err == nil {
+		return models.MaterialVersionTag{}, customerrors.ErrConflict
+	}
+	if !errors.Is(err, customerrors.ErrNotFound) {
+		return models.MaterialVersionTag{}, err
+	}
+
+	if err = s.repos.CreateMaterialVersionTag(ctx, materialID, versionID, name); err != nil {
+		return models.MaterialVersionTag{}, err
+	}
+	tag, err := s.repos.GetMaterialVersionTagByName(ctx, materialID, name)
+	if err != nil {
+		return models.MaterialVersionTag{}, err
+	}
+	return toMaterialVersionTag(tag), nil
+}
+
+func (s *Services) DeleteMaterialVersionTag(ctx context.Context, materialID, versionID int64, name string, req models.DeleteMaterialVersionTagRequest) error {
+	if err := s.checkMaterialOwner(ctx, materialID, req.TeacherID); err != nil {
+		return err
+	}
+	if err := s.checkTeacherActive(ctx, req.TeacherID); err != nil {
+		return err
+	}
+	if _, err := s.getMaterialVersion(ctx, materialID, versionID); err != nil {
+		return err
+	}
+	return s.repos.DeleteMaterialVersionTag(ctx, versionID, name)
+}
+
+// GetMaterialVersionByTag resolves a tag name to the version it marks.
+func (s *Services) GetMaterialVersionByTag(ctx context.Context, materialID int64, name string) (models.MaterialVersion, error) {
+	tag, err := s.repos.GetMaterialVersionTagByName(ctx, materialID, name)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+	version, err := s.repos.GetMaterialVersionByID(ctx, tag.MaterialVersionID)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+	return toMaterialVersion(version), nil
+}
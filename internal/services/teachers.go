@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+func toTeacher(t queries.Teacher) models.Teacher {
+	return models.Teacher{
+		ID:            t.ID,
+		Name:          t.Name,
+		CreatedAt:     t.CreatedAt,
+		DeactivatedAt: nullTimeToPointer(t.DeactivatedAt),
+	}
+}
+
+// checkTeacherActive returns ErrLocked when the teacher is deactivated, which
+// handlers answer with 403 Forbidden.
+// A deactivated teacher's materials stay readable, but every change to a
+// material checks its owner with this first.
+func (s *Services) checkTeacherActive(ctx context.Context, teacherID int64) error {
+	teacher, err := s.repos.GetTeacherByID(ctx, teacherID)
+	if err != nil {
+		return err
+	}
+	if teacher.DeactivatedAt.Valid {
+		return customerrors.ErrLocked
+	}
+	return nil
+}
+
+func (s *Services) CreateTeacher(ctx context.Context, req models.CreateTeacherRequest) (models.Teacher, error) {
+	teacherID, err := s.repos.CreateTeacher(ctx, req.Name)
+	if err != nil {
+		return models.Teacher{}, err
+	}
+	return s.GetTeacherByID(ctx, teacherID)
+}
+
+func (s *Services) UpdateTeacher(ctx context.Context, teacherID int64, req models.UpdateTeacherRequest) (models.Teacher, error) {
+	if _, err := s.repos.GetTeacherByID(ctx, teacherID); err != nil {
+		return models.Teacher{}, err
+	}
+	if req.Name != nil {
+		if err := s.repos.UpdateTeacherName(ctx, teacherID, *req.Name); err != nil {
+			return models.Teacher{}, err
+		}
+	}
+	return s.GetTeacherByID(ctx, teacherID)
+}
+
+// DeactivateTeacher stops the teacher's materials from being edited. It
+// returns ErrConflict when the teacher is already deactivated.
+func (s *Services) DeactivateTeacher(ctx context.Context, teacherID int64) (models.Teacher, error) {
+	if _, err := s.repos.GetTeacherByID(ctx, teacherID); err != nil {
+		return models.Teacher{}, err
+	}
+	if err := s.repos.DeactivateTeacher(ctx, teacherID); err != nil {
+		return models.Teacher{}, err
+	}
+	return s.GetTeacherByID(ctx, teacherID)
+}
+
+// ReactivateTeacher undoes DeactivateTeacher. It returns ErrConflict when
+// the teacher is already active.
+func (s *Services) ReactivateTeacher(ctx context.Context, teacherID int64) (models.Teacher, error) {
+	if _, err := s.repos.GetTeacherByID(ctx, teacherID); err != nil {
+		return models.Teacher{}, err
+	}
+	if err := s.repos.ReactivateTeacher(ctx, teacherID); err != nil {
+		return models.Teacher{}, err
+	}
+	return s.GetTeacherByID(ctx, teacherID)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/didrikolofsson/materials/generated/queries"
+	"github.com/didrikolofsson/materials/internal/diff"
+	customerrors "github.com/didrikolofsson/materials/internal/errors"
+	"github.com/didrikolofsson/materials/internal/models"
+)
+
+// getMaterialVersion loads a published version and makes sure it belongs to
+// the material, so drafts and version IDs from other materials read as not
+// found.
+func (s *Services) getMaterialVersion(ctx context.Context, materialID, versionID int64) (queries.GetMaterialVersionByIDRow, error) {
+	version, err := s.repos.GetMaterialVersionByID(ctx, versionID)
+	if err != nil {
+		return queries.GetMaterialVersionByIDRow{}, err
+	}
+	if version.MaterialID != materialID || version.IsDraft {
+		return queries.GetMaterialVersionByIDRow{}, customerrors.ErrNotFound
+	}
+	return version, nil
+}
+
+func toMaterialVersion(v queries.GetMaterialVersionByIDRow) models.MaterialVersion {
+	return models.MaterialVersion{
+		ID:              v.ID,
+		Title:           v.Title,
+		Description:     nullStringToPointer(v.Description),
+		Summary:         nullStringToPointer(v.Summary),
+		Content:         v.Content,
+		VersionNumber:   int(v.VersionNumber),
+		IsMain:          v.IsMain,
+		CreatedAt:       v.CreatedAt,
+		ChangeNote:      nullStringToPointer(v.ChangeNote),
+		AuthorTeacherID: nullInt64ToPointer(v.AuthorTeacherID),
+		IsDraft:         v.IsDraft,
+		BaseVersionID:   nullInt64ToPointer(v.BaseVersionID),
+	}
+}
+
+// RevertMaterialVersion restores an earlier version by copying it into a new
+// main version, so history stays append-only and the highest version number
+// is always the live one.
+func (s *Services) RevertMaterialVersion(ctx context.Context, materialID, versionID int64, req models.RevertMaterialVersionRequest) (models.MaterialVersion, error) {
+	material, err := s.repos.GetMaterialByID(ctx, materialID)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+	if material.TeacherID != req.TeacherID {
+		return models.MaterialVersion{}, customerrors.ErrForbidden
+	}
+	if err = s.checkTeacherActive(ctx, material.TeacherID); err != nil {
+		return models.MaterialVersion{}, err
+	}
+
+	version, err := s.getMaterialVersion(ctx, materialID, versionID)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+	if material.CurrentVersionID.Valid && material.CurrentVersionID.Int64 == version.ID {
+		return models.MaterialVersion{}, customerrors.ErrConflict
+	}
+
+	note := fmt.Sprintf("Reverted from v%d", version.VersionNumber)
+	revertedID, err := s.repos.CreateMainMaterialVersion(
+		ctx,
+		materialID,
+		req.TeacherID,
+		nil,
+		version.Title,
+		nullStringToPointer(version.Summary),
+		nullStringToPointer(version.Description),
+		version.Content,
+		&note,
+	)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+
+	reverted, err := s.repos.GetMaterialVersionByID(ctx, revertedID)
+	if err != nil {
+		return models.MaterialVersion{}, err
+	}
+	return toMaterialVersion(reverted), nil
+}
+
+// DiffMaterialVersions compares two versions of a material. The versions may
+// be given in either order; the diff always reads from the first to the second.
+func (s *Services) DiffMaterialVersions(ctx context.Context, materialID, fromVersionID, toVersionID int64) (models.MaterialVersionDiff, error) {
+	if _, err := s.repos.GetMaterialByID(ctx, materialID); err != nil {
+		return models.MaterialVersionDiff{}, err
+	}
+	from, err := s.getMaterialVersion(ctx, materialID, fromVersionID)
+	if err != nil {
+		return models.MaterialVersionDiff{}, err
+	}
+	to, err := s.getMaterialVersion(ctx, materialID, toVersionID)
+	if err != nil {
+		return models.MaterialVersionDiff{}, err
+	}
+
+	fromFields, toFields := versionFields(from), versionFields(to)
+	return models.MaterialVersionDiff{
+		MaterialID:    materialID,
+		FromVersionID: from.ID,
+		ToVersionID:   to.ID,
+		Changes:       diffMaterialFields(fromFields, toFields),
+		Patch:         materialPatch(fromFields, toFields),
+	}, nil
+}
+
+// materialPatch renders the changes between two sets of fields as a unified
+// diff, treating each field as a file. A missing field is an empty file and
+// the single line fields get a trailing newline so they diff as whole lines.
+func materialPatch(from, to materialFields) string {
+	line := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s + "\n"
+	}
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"title", line(&from.Title), line(&to.Title)},
+		{"summary", line(from.Summary), line(to.Summary)},
+		{"description", line(from.Description), line(to.Description)},
+		{"content", from.Content, to.Content},
+	}
+
+	var patch strings.Builder
+	for _, f := range fields {
+		patch.WriteString(diff.Unified(f.from, f.to, "a/"+f.name, "b/"+f.name))
+	}
+	return patch.String()
+}
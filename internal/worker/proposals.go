@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/didrikolofsson/materials/internal/models"
+	"github.com/didrikolofsson/materials/internal/services"
+)
+
+// Notifier delivers reminders about proposals waiting for review.
+type Notifier interface {
+	NotifyProposalReminder(ctx context.Context, proposal models.MaterialProposal) error
+}
+
+// LogNotifier writes reminders to the log.
+type LogNotifier struct{}
+
+func (LogNotifier) NotifyProposalReminder(ctx context.Context, proposal models.MaterialProposal) error {
+	log.Printf(
+		"Reminder: proposal %d on material %d by teacher %d awaits review by teacher %d since %s",
+		proposal.ID,
+		proposal.MaterialID,
+		proposal.AuthorTeacherID,
+		proposal.OwnerTeacherID,
+		proposal.CreatedAt.Format(time.RFC3339),
+	)
+	return nil
+}
+
+// ProposalReminders reminds owners of proposals that have been pending for
+// longer than after, and again every after until they are decided. A zero
+// after disables reminders.
+func ProposalReminders(svc *services.Services, notifier Notifier, after time.Duration) Job {
+	return Job{
+		Name: "proposal-reminders",
+		Run: func(ctx context.Context) error {
+			if after <= 0 {
+				return nil
+			}
+			proposals, err := svc.ListIdleMaterialProposals(ctx, time.Now().Add(-after))
+			if err != nil {
+				return err
+			}
+			for _, proposal := range proposals {
+				if err := notifier.NotifyProposalReminder(ctx, proposal); err != nil {
+					return err
+				}
+				if err := svc.MarkMaterialProposalReminded(ctx, proposal.ID); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// ProposalExpiry closes proposals that have been pending for longer than
+// after. A zero after disables expiry, which cannot be undone.
+func ProposalExpiry(svc *services.Services, after time.Duration) Job {
+	return Job{
+		Name: "proposal-expiry",
+		Run: func(ctx context.Context) error {
+			if after <= 0 {
+				return nil
+			}
+			expired, err := svc.ExpireMaterialProposals(ctx, time.Now().Add(-after))
+			if err != nil {
+				return err
+			}
+			if expired > 0 {
+				log.Printf("Expired %d idle proposals", expired)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/didrikolofsson/materials/internal/services"
+)
+
+// VersionRetention deletes the material versions policy does not keep.
+func VersionRetention(svc *services.Services, policy services.VersionRetentionPolicy) Job {
+	return Job{
+		Name: "version-retention",
+		Run: func(ctx context.Context) error {
+			result, err := svc.ApplyVersionRetention(ctx, policy, time.Now(), false)
+			if err != nil {
+				return err
+			}
+			if result.VersionsDeleted > 0 || result.ContentsDeleted > 0 {
+				log.Printf(
+					"Deleted %d retired versions across %d materials and %d orphaned contents",
+					result.VersionsDeleted,
+					result.Materials,
+					result.ContentsDeleted,
+				)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,55 @@
+// Package worker runs periodic background jobs next to the HTTP server.
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type Job struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type Worker struct {
+	interval time.Duration
+	jobs     []Job
+}
+
+func New(interval time.Duration, jobs ...Job) *Worker {
+	return &Worker{
+		interval: interval,
+		jobs:     jobs,
+	}
+}
+
+// Run runs every job once per interval until ctx is cancelled. A failing job
+// is logged and tried again on the next tick.
+func (w *Worker) Run(ctx context.Context) {
+	if len(w.jobs) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Printf("Worker is running %d jobs every %s", len(w.jobs), w.interval)
+	for {
+		for _, job := range w.jobs {
+			if ctx.Err() != nil {
+				break
+			}
+			if err := job.Run(ctx); err != nil {
+				log.Printf("Job %s failed: %v", job.Name, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,12 @@
+ALTER TABLE material_proposals DROP CHECK undecided_when_pending;
+ALTER TABLE material_proposals DROP CHECK decided_when_not_pending;
+ALTER TABLE material_proposals
+ADD CONSTRAINT not_pending_when_decided CHECK (
+		status != "PENDING"
+		OR decided_at IS NOT NULL
+	);
+ALTER TABLE material_proposals
+ADD CONSTRAINT not_rejected_when_decided CHECK (
+		status != "REJECTED"
+		OR decided_at IS NOT NULL
+	);
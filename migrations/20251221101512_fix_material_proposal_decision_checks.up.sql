@@ -0,0 +1,19 @@
+-- The original checks required decided_at on PENDING rows, which rejected every new proposal
+ALTER TABLE material_proposals DROP CHECK not_pending_when_decided;
+ALTER TABLE material_proposals DROP CHECK not_rejected_when_decided;
+ALTER TABLE material_proposals
+ADD CONSTRAINT undecided_when_pending CHECK (
+		status != "PENDING"
+		OR (
+			decided_at IS NULL
+			AND decided_by_teacher_id IS NULL
+		)
+	);
+ALTER TABLE material_proposals
+ADD CONSTRAINT decided_when_not_pending CHECK (
+		status = "PENDING"
+		OR (
+			decided_at IS NOT NULL
+			AND decided_by_teacher_id IS NOT NULL
+		)
+	);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS material_proposal_comments;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS material_proposal_comments (
+	id BIGINT PRIMARY KEY AUTO_INCREMENT,
+	proposal_id BIGINT NOT NULL,
+	author_teacher_id BIGINT NOT NULL,
+	parent_comment_id BIGINT NULL,
+	line_start INT NULL,
+	line_end INT NULL,
+	body TEXT NOT NULL,
+	resolved_by_teacher_id BIGINT NULL,
+	resolved_at TIMESTAMP NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_comment_proposal_id FOREIGN KEY (proposal_id) REFERENCES material_proposals(id) ON DELETE CASCADE,
+	CONSTRAINT fk_comment_author_teacher_id FOREIGN KEY (author_teacher_id) REFERENCES teachers(id),
+	CONSTRAINT fk_comment_parent_comment_id FOREIGN KEY (parent_comment_id) REFERENCES material_proposal_comments(id) ON DELETE CASCADE,
+	CONSTRAINT fk_comment_resolved_by_teacher_id FOREIGN KEY (resolved_by_teacher_id) REFERENCES teachers(id),
+	CONSTRAINT valid_comment_line_range CHECK (
+		(
+			line_start IS NULL
+			AND line_end IS NULL
+		)
+		OR (
+			line_start >= 1
+			AND line_end >= line_start
+		)
+	),
+	CONSTRAINT resolved_comment_has_resolver CHECK (
+		(resolved_at IS NULL) = (resolved_by_teacher_id IS NULL)
+	)
+);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS material_proposal_revisions;
+ALTER TABLE material_proposals DROP FOREIGN KEY fk_prop_superseded_by_proposal_id;
+ALTER TABLE material_proposals DROP COLUMN superseded_by_proposal_id;
+UPDATE material_proposals
+SET status = "REJECTED"
+WHERE status IN ("WITHDRAWN", "SUPERSEDED");
+ALTER TABLE material_proposals
+MODIFY status ENUM("PENDING", "APPROVED", "REJECTED") NOT NULL DEFAULT "PENDING";
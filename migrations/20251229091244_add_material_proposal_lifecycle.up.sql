@@ -0,0 +1,45 @@
+ALTER TABLE material_proposals
+MODIFY status ENUM(
+		"PENDING",
+		"APPROVED",
+		"REJECTED",
+		"WITHDRAWN",
+		"SUPERSEDED"
+	) NOT NULL DEFAULT "PENDING";
+ALTER TABLE material_proposals
+ADD COLUMN superseded_by_proposal_id BIGINT NULL,
+	ADD CONSTRAINT fk_prop_superseded_by_proposal_id FOREIGN KEY (superseded_by_proposal_id) REFERENCES material_proposals(id);
+CREATE TABLE IF NOT EXISTS material_proposal_revisions (
+	id BIGINT PRIMARY KEY AUTO_INCREMENT,
+	proposal_id BIGINT NOT NULL,
+	revision_number INT NOT NULL,
+	material_version_id BIGINT NOT NULL,
+	title VARCHAR(255) NOT NULL,
+	summary VARCHAR(255) NULL,
+	description TEXT NULL,
+	content TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_revision_proposal_id FOREIGN KEY (proposal_id) REFERENCES material_proposals(id) ON DELETE CASCADE,
+	CONSTRAINT fk_revision_material_version_id FOREIGN KEY (material_version_id) REFERENCES material_versions(id),
+	CONSTRAINT unique_revision_number_per_proposal UNIQUE (proposal_id, revision_number)
+);
+-- Existing proposals start their history with what they currently propose
+INSERT INTO material_proposal_revisions (
+		proposal_id,
+		revision_number,
+		material_version_id,
+		title,
+		summary,
+		description,
+		content,
+		created_at
+	)
+SELECT id,
+	1,
+	material_version_id,
+	title,
+	summary,
+	description,
+	content,
+	created_at
+FROM material_proposals;
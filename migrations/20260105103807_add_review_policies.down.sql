@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS material_proposal_approvals;
+DROP TABLE IF EXISTS review_policy_reviewers;
+DROP TABLE IF EXISTS review_policies;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS review_policies (
+	id BIGINT PRIMARY KEY AUTO_INCREMENT,
+	material_id BIGINT NULL,
+	subject_id BIGINT NULL,
+	required_approvals INT NOT NULL DEFAULT 1,
+	owner_can_self_approve BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_policy_material_id FOREIGN KEY (material_id) REFERENCES materials(id) ON DELETE CASCADE,
+	CONSTRAINT fk_policy_subject_id FOREIGN KEY (subject_id) REFERENCES subjects(id) ON DELETE CASCADE,
+	CONSTRAINT unique_policy_per_material UNIQUE (material_id),
+	CONSTRAINT unique_policy_per_subject UNIQUE (subject_id),
+	CONSTRAINT policy_for_material_or_subject CHECK (
+		(material_id IS NULL) != (subject_id IS NULL)
+	),
+	CONSTRAINT positive_required_approvals CHECK (required_approvals >= 1)
+);
+CREATE TABLE IF NOT EXISTS review_policy_reviewers (
+	policy_id BIGINT NOT NULL,
+	teacher_id BIGINT NOT NULL,
+	PRIMARY KEY (policy_id, teacher_id),
+	CONSTRAINT fk_reviewer_policy_id FOREIGN KEY (policy_id) REFERENCES review_policies(id) ON DELETE CASCADE,
+	CONSTRAINT fk_reviewer_teacher_id FOREIGN KEY (teacher_id) REFERENCES teachers(id)
+);
+CREATE TABLE IF NOT EXISTS material_proposal_approvals (
+	proposal_id BIGINT NOT NULL,
+	teacher_id BIGINT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (proposal_id, teacher_id),
+	CONSTRAINT fk_approval_proposal_id FOREIGN KEY (proposal_id) REFERENCES material_proposals(id) ON DELETE CASCADE,
+	CONSTRAINT fk_approval_teacher_id FOREIGN KEY (teacher_id) REFERENCES teachers(id)
+);
+-- Proposals approved before policies existed had a single approval by the owner
+INSERT INTO material_proposal_approvals (proposal_id, teacher_id, created_at)
+SELECT id,
+	decided_by_teacher_id,
+	decided_at
+FROM material_proposals
+WHERE status = "APPROVED";
@@ -0,0 +1,23 @@
+UPDATE material_proposals
+SET status = "REJECTED",
+	decided_by_teacher_id = owner_teacher_id
+WHERE status = "EXPIRED";
+ALTER TABLE material_proposals DROP CHECK decider_unless_expired;
+ALTER TABLE material_proposals DROP CHECK decided_when_not_pending;
+ALTER TABLE material_proposals
+ADD CONSTRAINT decided_when_not_pending CHECK (
+		status = "PENDING"
+		OR (
+			decided_at IS NOT NULL
+			AND decided_by_teacher_id IS NOT NULL
+		)
+	);
+ALTER TABLE material_proposals DROP COLUMN reminded_at;
+ALTER TABLE material_proposals
+MODIFY status ENUM(
+		"PENDING",
+		"APPROVED",
+		"REJECTED",
+		"WITHDRAWN",
+		"SUPERSEDED"
+	) NOT NULL DEFAULT "PENDING";
@@ -0,0 +1,23 @@
+ALTER TABLE material_proposals
+MODIFY status ENUM(
+		"PENDING",
+		"APPROVED",
+		"REJECTED",
+		"WITHDRAWN",
+		"SUPERSEDED",
+		"EXPIRED"
+	) NOT NULL DEFAULT "PENDING";
+ALTER TABLE material_proposals
+ADD COLUMN reminded_at TIMESTAMP NULL;
+-- Expired proposals are closed by the background worker, not by a teacher
+ALTER TABLE material_proposals DROP CHECK decided_when_not_pending;
+ALTER TABLE material_proposals
+ADD CONSTRAINT decided_when_not_pending CHECK (
+		status = "PENDING"
+		OR decided_at IS NOT NULL
+	);
+ALTER TABLE material_proposals
+ADD CONSTRAINT decider_unless_expired CHECK (
+		status IN ("PENDING", "EXPIRED")
+		OR decided_by_teacher_id IS NOT NULL
+	);
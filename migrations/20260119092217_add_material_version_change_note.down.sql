@@ -0,0 +1 @@
+ALTER TABLE material_versions DROP COLUMN change_note;
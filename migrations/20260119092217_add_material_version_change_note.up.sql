@@ -0,0 +1,2 @@
+ALTER TABLE material_versions
+ADD COLUMN change_note VARCHAR(255) NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE materials DROP FOREIGN KEY fk_original_material_id;
+ALTER TABLE materials
+ADD CONSTRAINT fk_original_material_id FOREIGN KEY (original_material_id) REFERENCES materials(id);
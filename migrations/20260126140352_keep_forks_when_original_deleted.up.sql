@@ -0,0 +1,4 @@
+-- Forks outlive the material they were copied from
+ALTER TABLE materials DROP FOREIGN KEY fk_original_material_id;
+ALTER TABLE materials
+ADD CONSTRAINT fk_original_material_id FOREIGN KEY (original_material_id) REFERENCES materials(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE materials DROP FOREIGN KEY fk_upstream_version_id;
+ALTER TABLE materials DROP COLUMN upstream_version_id;
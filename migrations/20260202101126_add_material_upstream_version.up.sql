@@ -0,0 +1,17 @@
+ALTER TABLE materials
+ADD COLUMN upstream_version_id BIGINT NULL;
+ALTER TABLE materials
+ADD CONSTRAINT fk_upstream_version_id FOREIGN KEY (upstream_version_id) REFERENCES material_versions(id) ON DELETE SET NULL;
+-- Assume existing forks were last synced to whatever was the original's
+-- newest version when the fork was created
+UPDATE materials f
+SET upstream_version_id = (
+		SELECT mv.id
+		FROM material_versions mv
+		WHERE mv.material_id = f.original_material_id
+			AND mv.created_at <= f.created_at
+		ORDER BY mv.version_number DESC
+		LIMIT 1
+	)
+WHERE f.original_material_id IS NOT NULL
+	AND f.original_material_id <> f.id;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS material_version_tags (
+	id BIGINT PRIMARY KEY AUTO_INCREMENT,
+	material_id BIGINT NOT NULL,
+	material_version_id BIGINT NOT NULL,
+	name VARCHAR(100) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_tag_material_id FOREIGN KEY (material_id) REFERENCES materials(id) ON DELETE CASCADE,
+	CONSTRAINT fk_tag_material_version_id FOREIGN KEY (material_version_id) REFERENCES material_versions(id) ON DELETE CASCADE,
+	CONSTRAINT unique_tag_name_per_material UNIQUE (material_id, name)
+);
@@ -0,0 +1,4 @@
+DELETE FROM material_versions
+WHERE is_draft = TRUE;
+ALTER TABLE material_versions DROP CHECK draft_is_not_main;
+ALTER TABLE material_versions DROP COLUMN is_draft;
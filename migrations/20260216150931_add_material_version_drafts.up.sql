@@ -0,0 +1,9 @@
+ALTER TABLE material_versions
+ADD COLUMN is_draft BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE material_versions
+ADD CONSTRAINT draft_is_not_main CHECK (
+		NOT (
+			is_draft
+			AND is_main
+		)
+	);
@@ -0,0 +1,31 @@
+ALTER TABLE material_proposal_revisions
+ADD COLUMN content TEXT NULL
+AFTER description;
+UPDATE material_proposal_revisions r
+	INNER JOIN material_contents c ON c.hash = r.content_hash
+SET r.content = c.body;
+ALTER TABLE material_proposal_revisions
+MODIFY content TEXT NOT NULL,
+	DROP FOREIGN KEY fk_revision_content_hash,
+	DROP COLUMN content_hash;
+ALTER TABLE material_proposals
+ADD COLUMN content TEXT NULL
+AFTER description;
+UPDATE material_proposals p
+	INNER JOIN material_contents c ON c.hash = p.content_hash
+SET p.content = c.body;
+ALTER TABLE material_proposals
+MODIFY content TEXT NOT NULL,
+	DROP FOREIGN KEY fk_prop_content_hash,
+	DROP COLUMN content_hash;
+ALTER TABLE material_versions
+ADD COLUMN content TEXT NULL
+AFTER description;
+UPDATE material_versions v
+	INNER JOIN material_contents c ON c.hash = v.content_hash
+SET v.content = c.body;
+ALTER TABLE material_versions
+MODIFY content TEXT NOT NULL,
+	DROP FOREIGN KEY fk_version_content_hash,
+	DROP COLUMN content_hash;
+DROP TABLE IF EXISTS material_contents;
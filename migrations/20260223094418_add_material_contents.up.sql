@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS material_contents (
+	hash CHAR(64) PRIMARY KEY,
+	body TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- Bodies are keyed by the hex encoded SHA-256 of their text, so every
+-- distinct body is stored once however many rows use it
+INSERT IGNORE INTO material_contents (hash, body)
+SELECT SHA2(content, 256),
+	content
+FROM material_versions
+UNION
+SELECT SHA2(content, 256),
+	content
+FROM material_proposals
+UNION
+SELECT SHA2(content, 256),
+	content
+FROM material_proposal_revisions;
+ALTER TABLE material_versions
+ADD COLUMN content_hash CHAR(64) NULL;
+UPDATE material_versions
+SET content_hash = SHA2(content, 256);
+ALTER TABLE material_versions
+MODIFY content_hash CHAR(64) NOT NULL,
+	ADD CONSTRAINT fk_version_content_hash FOREIGN KEY (content_hash) REFERENCES material_contents(hash),
+	DROP COLUMN content;
+ALTER TABLE material_proposals
+ADD COLUMN content_hash CHAR(64) NULL;
+UPDATE material_proposals
+SET content_hash = SHA2(content, 256);
+ALTER TABLE material_proposals
+MODIFY content_hash CHAR(64) NOT NULL,
+	ADD CONSTRAINT fk_prop_content_hash FOREIGN KEY (content_hash) REFERENCES material_contents(hash),
+	DROP COLUMN content;
+ALTER TABLE material_proposal_revisions
+ADD COLUMN content_hash CHAR(64) NULL;
+UPDATE material_proposal_revisions
+SET content_hash = SHA2(content, 256);
+ALTER TABLE material_proposal_revisions
+MODIFY content_hash CHAR(64) NOT NULL,
+	ADD CONSTRAINT fk_revision_content_hash FOREIGN KEY (content_hash) REFERENCES material_contents(hash),
+	DROP COLUMN content;
@@ -0,0 +1,2 @@
+ALTER TABLE material_versions DROP FOREIGN KEY fk_version_author_teacher_id;
+ALTER TABLE material_versions DROP COLUMN author_teacher_id;
@@ -0,0 +1,21 @@
+ALTER TABLE material_versions
+ADD COLUMN author_teacher_id BIGINT NULL,
+	ADD CONSTRAINT fk_version_author_teacher_id FOREIGN KEY (author_teacher_id) REFERENCES teachers(id);
+-- Merged proposals and fork copies were not written by the owner, and
+-- nothing records who did write them. Existing versions are only attributed
+-- to the owner where nobody else could have written them: on materials that
+-- are not forks, before the first proposal was merged. The rest stay NULL.
+UPDATE material_versions mv
+	INNER JOIN materials m ON m.id = mv.material_id
+SET mv.author_teacher_id = m.teacher_id
+WHERE (
+		m.original_material_id IS NULL
+		OR m.original_material_id = m.id
+	)
+	AND NOT EXISTS (
+		SELECT 1
+		FROM material_proposals mp
+		WHERE mp.material_id = m.id
+			AND mp.status = "APPROVED"
+			AND mp.decided_at <= mv.created_at
+	);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS material_main_versions;
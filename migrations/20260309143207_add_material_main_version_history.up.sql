@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS material_main_versions (
+	id BIGINT PRIMARY KEY AUTO_INCREMENT,
+	material_id BIGINT NOT NULL,
+	material_version_id BIGINT NULL,
+	became_main_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT fk_main_material_id FOREIGN KEY (material_id) REFERENCES materials(id) ON DELETE CASCADE,
+	-- A version cannot disappear from under the history; version retention
+	-- keeps the last main version of every day and removes the history rows
+	-- of the versions it retires itself
+	CONSTRAINT fk_main_material_version_id FOREIGN KEY (material_version_id) REFERENCES material_versions(id) ON DELETE RESTRICT,
+	INDEX idx_main_material_became_main_at (material_id, became_main_at)
+);
+-- Earlier main version changes were not recorded. Published versions became
+-- main when they were created, except where an older version was made main
+-- again, which is assumed to have happened now.
+INSERT INTO material_main_versions (
+		material_id,
+		material_version_id,
+		became_main_at
+	)
+SELECT material_id,
+	id,
+	created_at
+FROM material_versions
+WHERE is_draft = FALSE
+ORDER BY created_at ASC,
+	id ASC;
+INSERT INTO material_main_versions (material_id, material_version_id)
+SELECT m.id,
+	m.current_version_id
+FROM materials m
+WHERE m.current_version_id IS NOT NULL
+	AND m.current_version_id <> (
+		SELECT mv.id
+		FROM material_versions mv
+		WHERE mv.material_id = m.id
+			AND mv.is_draft = FALSE
+		ORDER BY mv.version_number DESC
+		LIMIT 1
+	);
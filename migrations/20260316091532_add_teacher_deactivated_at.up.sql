@@ -0,0 +1,2 @@
+ALTER TABLE teachers
+ADD COLUMN deactivated_at TIMESTAMP NULL;
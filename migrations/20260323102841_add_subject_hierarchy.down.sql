@@ -0,0 +1,2 @@
+ALTER TABLE subjects DROP FOREIGN KEY fk_parent_subject_id;
+ALTER TABLE subjects DROP COLUMN parent_subject_id;
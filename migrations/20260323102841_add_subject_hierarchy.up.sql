@@ -0,0 +1,3 @@
+ALTER TABLE subjects
+ADD COLUMN parent_subject_id BIGINT NULL,
+	ADD CONSTRAINT fk_parent_subject_id FOREIGN KEY (parent_subject_id) REFERENCES subjects(id);
@@ -0,0 +1,2 @@
+ALTER TABLE material_versions DROP FOREIGN KEY fk_version_base_version_id;
+ALTER TABLE material_versions DROP COLUMN base_version_id;
@@ -0,0 +1,18 @@
+-- The main version a draft was started from, which publishing checks has not
+-- moved on since
+ALTER TABLE material_versions
+ADD COLUMN base_version_id BIGINT NULL;
+ALTER TABLE material_versions
+ADD CONSTRAINT fk_version_base_version_id FOREIGN KEY (base_version_id) REFERENCES material_versions(id) ON DELETE SET NULL;
+-- Existing drafts were started from whatever was main when they were created
+UPDATE material_versions d
+SET base_version_id = (
+		SELECT h.material_version_id
+		FROM material_main_versions h
+		WHERE h.material_id = d.material_id
+			AND h.became_main_at <= d.created_at
+		ORDER BY h.became_main_at DESC,
+			h.id DESC
+		LIMIT 1
+	)
+WHERE d.is_draft = TRUE;